@@ -0,0 +1,55 @@
+package main
+
+import (
+	"basicsystemmonitor/encoder"
+	"basicsystemmonitor/hundler"
+	"basicsystemmonitor/tui"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// textEncoder writes snapshots as the plain-text panels of the interactive view,
+// separated by blank lines.
+type textEncoder struct {
+	w       io.Writer
+	opts    tui.Options
+	written bool
+}
+
+func (e *textEncoder) Encode(s hundler.Snapshot) error {
+	if e.written {
+		if _, err := fmt.Fprintln(e.w); err != nil {
+			return err
+		}
+	}
+	e.written = true
+	_, err := io.WriteString(e.w, tui.RenderSnapshot(s, e.opts))
+	return err
+}
+
+// snapshotSource provides the snapshots written by runBatch, usually a *hundler.SnapshotStore.
+type snapshotSource interface {
+	Snapshot() hundler.Snapshot
+}
+
+// runBatch encodes a snapshot of the monitors every interval, like top -b. It returns
+// after iterations snapshots, or when ctx is cancelled; 0 iterations means no limit.
+// The first snapshot is taken after one interval so rates have a baseline.
+func runBatch(ctx context.Context, store snapshotSource, interval time.Duration, iterations int, enc encoder.Encoder) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; iterations == 0 || i < iterations; i++ {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+		if err := enc.Encode(store.Snapshot()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"basicsystemmonitor/hundler"
+	"basicsystemmonitor/tui"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeStore hands out snapshots numbered by their Cpu.Percent.
+type fakeStore struct {
+	calls int
+}
+
+func (s *fakeStore) Snapshot() hundler.Snapshot {
+	s.calls++
+	return hundler.Snapshot{Time: time.Unix(0, 0), Cpu: hundler.CpuStat{Percent: float64(s.calls)}}
+}
+
+// fakeEncoder records the snapshots it is given, and cancels after cancelAfter of them
+// if cancel is set.
+type fakeEncoder struct {
+	encoded     []hundler.Snapshot
+	cancel      context.CancelFunc
+	cancelAfter int
+	err         error
+}
+
+func (e *fakeEncoder) Encode(s hundler.Snapshot) error {
+	e.encoded = append(e.encoded, s)
+	if e.cancel != nil && len(e.encoded) == e.cancelAfter {
+		e.cancel()
+	}
+	return e.err
+}
+
+func TestRunBatchIterations(t *testing.T) {
+	store, enc := &fakeStore{}, &fakeEncoder{}
+	if err := runBatch(context.Background(), store, time.Millisecond, 3, enc); err != nil {
+		t.Fatal(err)
+	}
+	if len(enc.encoded) != 3 || store.calls != 3 {
+		t.Fatalf("encoded %d snapshots from %d reads, want 3", len(enc.encoded), store.calls)
+	}
+	for i, s := range enc.encoded {
+		if s.Cpu.Percent != float64(i+1) {
+			t.Errorf("snapshot %d is %v, want a fresh snapshot per iteration", i, s.Cpu.Percent)
+		}
+	}
+}
+
+func TestRunBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Without an iteration limit, runBatch runs until the context is cancelled.
+	enc := &fakeEncoder{cancel: cancel, cancelAfter: 2}
+
+	done := make(chan error, 1)
+	go func() { done <- runBatch(ctx, &fakeStore{}, time.Millisecond, 0, enc) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("runBatch after cancellation = %v, want nil for a clean exit", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("runBatch should return once the context is cancelled")
+	}
+	if len(enc.encoded) != 2 {
+		t.Errorf("encoded %d snapshots, want 2", len(enc.encoded))
+	}
+}
+
+func TestRunBatchEncodeError(t *testing.T) {
+	enc := &fakeEncoder{err: errors.New("broken pipe")}
+	if err := runBatch(context.Background(), &fakeStore{}, time.Millisecond, 5, enc); !errors.Is(err, enc.err) {
+		t.Errorf("runBatch = %v, want the encoder's error", err)
+	}
+	if len(enc.encoded) != 1 {
+		t.Errorf("encoded %d snapshots, want to stop after the failed one", len(enc.encoded))
+	}
+}
+
+func TestRunBatchTextSeparator(t *testing.T) {
+	var buf bytes.Buffer
+	opts := tui.Options{}
+	if err := runBatch(context.Background(), &fakeStore{}, time.Millisecond, 2, &textEncoder{w: &buf, opts: opts}); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &fakeStore{}
+	first := tui.RenderSnapshot(store.Snapshot(), opts)
+	second := tui.RenderSnapshot(store.Snapshot(), opts)
+	// Snapshots are separated by one blank line, with none before the first or after the last.
+	if want := first + "\n" + second; buf.String() != want {
+		t.Errorf("output =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
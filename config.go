@@ -1,6 +1,7 @@
 package main
 
 import (
+	"basicsystemmonitor/hundler"
 	"log"
 	"os"
 	"time"
@@ -10,9 +11,26 @@ import (
 
 // Config holds the application's configuration settings.
 type Config struct {
-	RefreshInterval        string `yaml:"refreshInterval"`
-	DiskPath               string `yaml:"diskPath"`
-	ProcessRefreshInterval string `yaml:"processRefreshInterval"`
+	RefreshInterval        string   `yaml:"refreshInterval"`
+	DiskPath               string   `yaml:"diskPath"`
+	DiskPaths              []string `yaml:"diskPaths"`
+	DiskDiscover           bool     `yaml:"diskDiscover"`      // Monitor every real mounted filesystem
+	DiskIgnoreFsTypes      []string `yaml:"diskIgnoreFsTypes"` // Filesystem types skipped by diskDiscover
+	DiskAlertPercent       float64  `yaml:"diskAlertPercent"`  // Space usage that raises an alert, 0 to disable
+	InodeAlertPercent      float64  `yaml:"inodeAlertPercent"` // Inode usage that raises an alert, 0 to disable
+	ProcessRefreshInterval string   `yaml:"processRefreshInterval"`
+	ProcessColumns         []string `yaml:"processColumns"` // Process table columns, e.g. [pid, user, name, cpu, mem]
+	ProcessFilter          string   `yaml:"processFilter"`  // Initial process filter, e.g. "user:postgres cpu>5"
+	ProcRoot               string   `yaml:"procRoot"`       // Where /proc is mounted, e.g. /host/proc in a container
+	ListenAddress          string   `yaml:"listenAddress"`  // HTTP address of the serve mode, e.g. :9184 or 127.0.0.1:9184
+
+	// Block devices to report I/O for, as glob patterns. An empty include list means all devices.
+	DiskDevices        []string `yaml:"diskDevices"`
+	DiskDevicesExclude []string `yaml:"diskDevicesExclude"`
+
+	// Network interfaces shown in the interface table, as glob patterns. An empty include list means all interfaces.
+	NetInterfaces        []string `yaml:"netInterfaces"`
+	NetInterfacesExclude []string `yaml:"netInterfacesExclude"`
 }
 
 // DefaultConfig returns a Config struct with default values.
@@ -21,6 +39,12 @@ func DefaultConfig() Config {
 		RefreshInterval:        "1s",
 		DiskPath:               "/",
 		ProcessRefreshInterval: "3s",
+		ProcRoot:               "/proc",
+		ListenAddress:          ":9184",
+		DiskDevicesExclude:     []string{"loop*", "ram*"},
+		DiskIgnoreFsTypes:      hundler.DefaultIgnoredFsTypes,
+		DiskAlertPercent:       90,
+		InodeAlertPercent:      90,
 	}
 }
 
@@ -51,6 +75,18 @@ func (c *Config) GetRefreshInterval() (time.Duration, error) {
 	return time.ParseDuration(c.RefreshInterval)
 }
 
+// GetDiskPaths returns the paths to monitor, falling back to the single DiskPath
+// when no DiskPaths list is configured.
+func (c *Config) GetDiskPaths() []string {
+	if len(c.DiskPaths) > 0 {
+		return c.DiskPaths
+	}
+	if c.DiskPath != "" {
+		return []string{c.DiskPath}
+	}
+	return nil
+}
+
 // GetProcessRefreshInterval parses the ProcessRefreshInterval string into a time.Duration.
 func (c *Config) GetProcessRefreshInterval() (time.Duration, error) {
 	return time.ParseDuration(c.ProcessRefreshInterval)
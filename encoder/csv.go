@@ -0,0 +1,99 @@
+package encoder
+
+import (
+	"basicsystemmonitor/hundler"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// csvEncoder writes one row per record under a header written before the first row.
+//
+// The columns are the scalar fields of Record, named after their JSON paths with "_"
+// instead of ".", followed by two columns per filesystem (disk:<path>:used and
+// disk:<path>:used_percent) and four per top process (proc<N>_pid, proc<N>_name,
+// proc<N>_cpu_percent, proc<N>_memory_bytes). Filesystems are fixed by the first record;
+// cells of filesystems missing later on are left empty, as are those of missing processes.
+type csvEncoder struct {
+	w     *csv.Writer
+	opts  Options
+	disks []string // Paths of the filesystem columns, set by the first record
+}
+
+func newCSVEncoder(w io.Writer, opts Options) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w), opts: opts}
+}
+
+// csvScalarHeader names the columns written for every record, in order.
+var csvScalarHeader = []string{
+	"schema", "timestamp", "hostname",
+	"cpu_percent",
+	"cpu_times_user", "cpu_times_system", "cpu_times_idle", "cpu_times_nice", "cpu_times_iowait",
+	"cpu_times_irq", "cpu_times_softirq", "cpu_times_steal", "cpu_times_guest", "cpu_times_guest_nice",
+	"load_load1", "load_load5", "load_load15",
+	"memory_total", "memory_used", "memory_used_percent", "memory_available", "memory_swap_total", "memory_swap_used",
+	"network_bytes_sent_per_sec", "network_bytes_recv_per_sec",
+}
+
+func (e *csvEncoder) Encode(s hundler.Snapshot) error {
+	r := NewRecord(s, e.opts)
+	if e.disks == nil {
+		e.disks = []string{}
+		header := append([]string(nil), csvScalarHeader...)
+		for _, d := range r.Disks {
+			e.disks = append(e.disks, d.Path)
+			header = append(header, "disk:"+d.Path+":used", "disk:"+d.Path+":used_percent")
+		}
+		for i := 1; i <= e.opts.TopProcesses; i++ {
+			p := fmt.Sprintf("proc%d_", i)
+			header = append(header, p+"pid", p+"name", p+"cpu_percent", p+"memory_bytes")
+		}
+		if err := e.w.Write(header); err != nil {
+			return err
+		}
+	}
+
+	t := r.CPU.Times
+	row := []string{
+		strconv.Itoa(r.Schema), r.Timestamp, r.Hostname,
+		formatFloat(r.CPU.Percent),
+		formatFloat(t.User), formatFloat(t.System), formatFloat(t.Idle), formatFloat(t.Nice), formatFloat(t.Iowait),
+		formatFloat(t.Irq), formatFloat(t.Softirq), formatFloat(t.Steal), formatFloat(t.Guest), formatFloat(t.GuestNice),
+		formatFloat(r.Load.Load1), formatFloat(r.Load.Load5), formatFloat(r.Load.Load15),
+		formatUint(r.Memory.Total), formatUint(r.Memory.Used), formatFloat(r.Memory.UsedPercent), formatUint(r.Memory.Available),
+		formatUint(r.Memory.SwapTotal), formatUint(r.Memory.SwapUsed),
+		formatFloat(r.Network.BytesSentPerSec), formatFloat(r.Network.BytesRecvPerSec),
+	}
+	for _, path := range e.disks {
+		used, percent := "", ""
+		for _, d := range r.Disks {
+			if d.Path == path {
+				used, percent = formatUint(d.Used), formatFloat(d.UsedPercent)
+				break
+			}
+		}
+		row = append(row, used, percent)
+	}
+	for i := 0; i < e.opts.TopProcesses; i++ {
+		if i < len(r.Processes) {
+			p := r.Processes[i]
+			row = append(row, strconv.Itoa(int(p.Pid)), p.Name, formatFloat(p.CPUPercent), formatUint(p.MemoryBytes))
+		} else {
+			row = append(row, "", "", "", "")
+		}
+	}
+	if err := e.w.Write(row); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
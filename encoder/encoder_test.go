@@ -0,0 +1,178 @@
+package encoder
+
+import (
+	"basicsystemmonitor/hundler"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSnapshot() hundler.Snapshot {
+	return hundler.Snapshot{
+		Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		Cpu: hundler.CpuStat{Percent: 12.5, PerCore: []float64{10, 15},
+			Times: hundler.CpuTimes{User: 8, System: 3, Idle: 87.5, Iowait: 1, Steal: 0.5}},
+		Ram: hundler.RamStat{Total: 8000, Used: 2000, UsedPercent: 25, Available: 6000},
+		Disks: []hundler.DiskStat{
+			{Path: "/", Device: "/dev/sda1", Fstype: "ext4", Total: 100, Used: 40, Free: 60, UsedPercent: 40},
+			{Path: "/var", Device: "/dev/sda2", Fstype: "xfs", Total: 100, Used: 90, Free: 10, UsedPercent: 90},
+		},
+		DiskIO:     []hundler.DiskIOStat{{Name: "sda", ReadBytesPerSec: 4096, AwaitMs: 1.5}},
+		Net:        hundler.NetStat{BytesSentPerSec: 100, BytesRecvPerSec: 200},
+		Interfaces: []hundler.InterfaceStat{{Name: "eth0", BytesSentPerSec: 100, BytesRecvPerSec: 200}},
+		Load:       hundler.LoadStat{Load1: 0.5, Load5: 0.25, Load15: 0.125, Uptime: 90 * time.Minute},
+		Pressure: hundler.PressureStat{
+			CPU: hundler.PressureResource{Available: true, Some: hundler.PressureLine{Avg10: 2.5, Total: 3 * time.Second}},
+			IO:  hundler.PressureResource{Available: true, HasFull: true, Full: hundler.PressureLine{Avg60: 1}},
+		},
+		Processes: []hundler.ProcessStat{
+			{Pid: 1, Name: "init", Username: "root", CPUPercent: 0.1},
+			{Pid: 20, Name: "make", Username: "ci", CPUPercent: 95, MemoryBytes: 1 << 20},
+			{Pid: 30, Name: "cc1", Username: "ci", CPUPercent: 40},
+		},
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := New("json", &buf, Options{Hostname: "web1", TopProcesses: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := enc.Encode(testSnapshot()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one per record:\n%s", len(lines), buf.String())
+	}
+	var r map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r["schema"] != float64(SchemaVersion) || r["timestamp"] != "2024-05-01T12:00:00+02:00" || r["hostname"] != "web1" {
+		t.Errorf("unexpected header fields: %v", r)
+	}
+	procs := r["processes"].([]any)
+	if len(procs) != 2 || procs[0].(map[string]any)["name"] != "make" || procs[1].(map[string]any)["pid"] != float64(30) {
+		t.Errorf("processes = %v, want make and cc1", procs)
+	}
+	disks := r["disks"].([]any)
+	if len(disks) != 2 || disks[1].(map[string]any)["used_percent"] != float64(90) {
+		t.Errorf("disks = %v", disks)
+	}
+	if io := r["disk_io"].([]any); len(io) != 1 || io[0].(map[string]any)["await_ms"] != 1.5 {
+		t.Errorf("disk_io = %v", io)
+	}
+	if times := r["cpu"].(map[string]any)["times"].(map[string]any); times["user"] != float64(8) || times["steal"] != 0.5 || times["guest_nice"] != float64(0) {
+		t.Errorf("cpu times = %v", times)
+	}
+	if r["load"].(map[string]any)["uptime_seconds"] != float64(5400) {
+		t.Errorf("load = %v", r["load"])
+	}
+	pressure := r["pressure"].(map[string]any)
+	if cpu := pressure["cpu"].(map[string]any); cpu["some"].(map[string]any)["total_seconds"] != float64(3) || cpu["full"] != nil {
+		t.Errorf("cpu pressure = %v, want some with 3s total and no full line", cpu)
+	}
+	if io := pressure["io"].(map[string]any); io["full"].(map[string]any)["avg60"] != float64(1) {
+		t.Errorf("io pressure = %v", io)
+	}
+	if r["network"].(map[string]any)["interfaces"].([]any)[0].(map[string]any)["name"] != "eth0" {
+		t.Errorf("network = %v", r["network"])
+	}
+}
+
+func TestJSONEncoderWithoutProcesses(t *testing.T) {
+	var buf bytes.Buffer
+	enc, _ := New("json", &buf, Options{Hostname: "web1"})
+	if err := enc.Encode(hundler.Snapshot{Processes: testSnapshot().Processes}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "processes") {
+		t.Errorf("processes should be omitted unless requested: %s", out)
+	}
+	// Empty lists are written as [] rather than null so consumers can always iterate.
+	if !strings.Contains(out, `"disks":[]`) || !strings.Contains(out, `"per_core":[]`) || !strings.Contains(out, `"disk_io":[]`) {
+		t.Errorf("empty lists should be encoded as []: %s", out)
+	}
+}
+
+func TestCSVEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := New("csv", &buf, Options{Hostname: "web1", TopProcesses: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(testSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	later := testSnapshot()
+	later.Disks = later.Disks[1:] // "/" went away
+	if err := enc.Encode(later); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header and two records", len(rows))
+	}
+	cols := make(map[string]int)
+	for i, name := range rows[0] {
+		cols[name] = i
+	}
+	if len(rows[0]) != len(csvScalarHeader)+2*2+4*4 {
+		t.Errorf("header has %d columns: %v", len(rows[0]), rows[0])
+	}
+	get := func(row int, col string) string {
+		i, ok := cols[col]
+		if !ok {
+			t.Fatalf("missing column %q in %v", col, rows[0])
+		}
+		return rows[row][i]
+	}
+	if get(1, "schema") != "1" || get(1, "timestamp") != "2024-05-01T12:00:00+02:00" || get(1, "cpu_percent") != "12.5" {
+		t.Errorf("unexpected record: %v", rows[1])
+	}
+	if get(1, "cpu_times_user") != "8" || get(1, "cpu_times_idle") != "87.5" || get(1, "cpu_times_guest_nice") != "0" {
+		t.Errorf("unexpected CPU time columns: %v", rows[1])
+	}
+	if get(1, "disk:/:used") != "40" || get(1, "disk:/var:used_percent") != "90" {
+		t.Errorf("unexpected disk columns: %v", rows[1])
+	}
+	if get(2, "disk:/:used") != "" || get(2, "disk:/var:used") != "90" {
+		t.Errorf("a filesystem that went away should leave empty cells: %v", rows[2])
+	}
+	if get(1, "proc1_name") != "make" || get(1, "proc1_memory_bytes") != "1048576" || get(1, "proc4_pid") != "" {
+		t.Errorf("unexpected process columns: %v", rows[1])
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := New("xml", &bytes.Buffer{}, Options{}); err == nil {
+		t.Error("New(\"xml\") should fail")
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	procs := testSnapshot().Processes
+	top := TopProcesses(procs, 5)
+	if len(top) != 3 || top[0].Pid != 20 || top[2].Pid != 1 {
+		t.Errorf("TopProcesses = %v", top)
+	}
+	if procs[0].Pid != 1 {
+		t.Error("TopProcesses should not reorder its input")
+	}
+	if TopProcesses(procs, 0) != nil {
+		t.Error("TopProcesses(0) should return nothing")
+	}
+}
@@ -0,0 +1,21 @@
+package encoder
+
+import (
+	"basicsystemmonitor/hundler"
+	"encoding/json"
+	"io"
+)
+
+// jsonEncoder writes JSON Lines: one Record object per line.
+type jsonEncoder struct {
+	enc  *json.Encoder
+	opts Options
+}
+
+func newJSONEncoder(w io.Writer, opts Options) *jsonEncoder {
+	return &jsonEncoder{enc: json.NewEncoder(w), opts: opts}
+}
+
+func (e *jsonEncoder) Encode(s hundler.Snapshot) error {
+	return e.enc.Encode(NewRecord(s, e.opts))
+}
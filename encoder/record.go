@@ -0,0 +1,287 @@
+// Package encoder turns monitor snapshots into machine-readable records, one per
+// refresh, for piping into tools such as jq or a spreadsheet.
+//
+// Every record carries SchemaVersion. Field names are part of the schema: they are only
+// ever added to within a version, and renaming or removing one bumps the version.
+package encoder
+
+import (
+	"basicsystemmonitor/hundler"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// SchemaVersion is the version of the record layout written by every encoder.
+const SchemaVersion = 1
+
+// Record is the machine-readable form of a snapshot.
+type Record struct {
+	Schema    int       `json:"schema"`
+	Timestamp string    `json:"timestamp"` // RFC 3339
+	Hostname  string    `json:"hostname"`
+	CPU       CPU       `json:"cpu"`
+	Load      Load      `json:"load"`
+	Memory    Memory    `json:"memory"`
+	Disks     []Disk    `json:"disks"`
+	DiskIO    []DiskIO  `json:"disk_io"`
+	Network   Network   `json:"network"`
+	Pressure  Pressure  `json:"pressure"`
+	Processes []Process `json:"processes,omitempty"` // Top processes by CPU, when requested
+}
+
+// CPU is the CPU usage in percent.
+type CPU struct {
+	Percent float64   `json:"percent"`
+	PerCore []float64 `json:"per_core"`
+	Times   CPUTimes  `json:"times"`
+}
+
+// CPUTimes breaks CPU time down by mode, in percent of all CPUs.
+type CPUTimes struct {
+	User      float64 `json:"user"`
+	System    float64 `json:"system"`
+	Idle      float64 `json:"idle"`
+	Nice      float64 `json:"nice"`
+	Iowait    float64 `json:"iowait"`
+	Irq       float64 `json:"irq"`
+	Softirq   float64 `json:"softirq"`
+	Steal     float64 `json:"steal"`
+	Guest     float64 `json:"guest"`
+	GuestNice float64 `json:"guest_nice"`
+}
+
+// Load holds the load averages, the run queue and the uptime.
+type Load struct {
+	Load1         float64 `json:"load1"`
+	Load5         float64 `json:"load5"`
+	Load15        float64 `json:"load15"`
+	ProcsRunning  int     `json:"procs_running"`
+	ProcsBlocked  int     `json:"procs_blocked"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+// Memory holds RAM and swap usage in bytes.
+type Memory struct {
+	Total       uint64  `json:"total"`
+	Used        uint64  `json:"used"`
+	UsedPercent float64 `json:"used_percent"`
+	Available   uint64  `json:"available"`
+	SwapTotal   uint64  `json:"swap_total"`
+	SwapUsed    uint64  `json:"swap_used"`
+}
+
+// Disk holds the usage of one filesystem in bytes.
+type Disk struct {
+	Path              string  `json:"path"`
+	Device            string  `json:"device"`
+	Fstype            string  `json:"fstype"`
+	Total             uint64  `json:"total"`
+	Used              uint64  `json:"used"`
+	Free              uint64  `json:"free"`
+	UsedPercent       float64 `json:"used_percent"`
+	InodesUsedPercent float64 `json:"inodes_used_percent"`
+}
+
+// DiskIO holds the throughput and latency of one block device.
+type DiskIO struct {
+	Name             string  `json:"name"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadOpsPerSec    float64 `json:"read_ops_per_sec"`
+	WriteOpsPerSec   float64 `json:"write_ops_per_sec"`
+	AwaitMs          float64 `json:"await_ms"`
+	UtilPercent      float64 `json:"util_percent"`
+}
+
+// Network holds network throughput in bytes per second.
+type Network struct {
+	BytesSentPerSec float64     `json:"bytes_sent_per_sec"`
+	BytesRecvPerSec float64     `json:"bytes_recv_per_sec"`
+	Interfaces      []Interface `json:"interfaces"`
+}
+
+// Interface holds the throughput of one network interface in bytes per second.
+type Interface struct {
+	Name            string  `json:"name"`
+	BytesSentPerSec float64 `json:"bytes_sent_per_sec"`
+	BytesRecvPerSec float64 `json:"bytes_recv_per_sec"`
+}
+
+// Pressure holds the Pressure Stall Information of each resource.
+type Pressure struct {
+	CPU    PressureResource `json:"cpu"`
+	Memory PressureResource `json:"memory"`
+	IO     PressureResource `json:"io"`
+}
+
+// PressureResource holds the pressure of one resource. Full is omitted when the kernel
+// doesn't report it, as for cpu on older kernels.
+type PressureResource struct {
+	Available bool          `json:"available"`
+	Some      PressureLine  `json:"some"`
+	Full      *PressureLine `json:"full,omitempty"`
+}
+
+// PressureLine holds the share of wall time stalled in percent and the total stall time.
+type PressureLine struct {
+	Avg10        float64 `json:"avg10"`
+	Avg60        float64 `json:"avg60"`
+	Avg300       float64 `json:"avg300"`
+	TotalSeconds float64 `json:"total_seconds"`
+}
+
+// Process holds the usage of one process.
+type Process struct {
+	Pid              int32   `json:"pid"`
+	Name             string  `json:"name"`
+	User             string  `json:"user"`
+	State            string  `json:"state"`
+	Cmdline          string  `json:"cmdline"`
+	CPUPercent       float64 `json:"cpu_percent"`
+	MemoryBytes      uint64  `json:"memory_bytes"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	FDs              int32   `json:"fds"`
+}
+
+// Options controls what goes into a record.
+type Options struct {
+	Hostname     string // Defaults to os.Hostname
+	TopProcesses int    // Number of processes to include, busiest first; 0 for none
+}
+
+// Encoder writes one record per snapshot.
+type Encoder interface {
+	Encode(s hundler.Snapshot) error
+}
+
+// New returns an encoder writing the named format to w: "json" for JSON Lines or "csv".
+func New(format string, w io.Writer, opts Options) (Encoder, error) {
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	switch format {
+	case "json":
+		return newJSONEncoder(w, opts), nil
+	case "csv":
+		return newCSVEncoder(w, opts), nil
+	}
+	return nil, fmt.Errorf("unknown output format %q (want json or csv)", format)
+}
+
+// NewRecord builds the record of a snapshot.
+func NewRecord(s hundler.Snapshot, opts Options) Record {
+	r := Record{
+		Schema:    SchemaVersion,
+		Timestamp: s.Time.Format(time.RFC3339),
+		Hostname:  opts.Hostname,
+		CPU:       CPU{Percent: s.Cpu.Percent, PerCore: s.Cpu.PerCore, Times: CPUTimes(s.Cpu.Times)},
+		Load: Load{
+			Load1:         s.Load.Load1,
+			Load5:         s.Load.Load5,
+			Load15:        s.Load.Load15,
+			ProcsRunning:  s.Load.ProcsRunning,
+			ProcsBlocked:  s.Load.ProcsBlocked,
+			UptimeSeconds: s.Load.Uptime.Seconds(),
+		},
+		Memory: Memory{
+			Total:       s.Ram.Total,
+			Used:        s.Ram.Used,
+			UsedPercent: s.Ram.UsedPercent,
+			Available:   s.Ram.Available,
+			SwapTotal:   s.Ram.SwapTotal,
+			SwapUsed:    s.Ram.SwapUsed,
+		},
+		Disks:  []Disk{},
+		DiskIO: []DiskIO{},
+		Network: Network{
+			BytesSentPerSec: s.Net.BytesSentPerSec,
+			BytesRecvPerSec: s.Net.BytesRecvPerSec,
+			Interfaces:      []Interface{},
+		},
+		Pressure: Pressure{
+			CPU:    newPressureResource(s.Pressure.CPU),
+			Memory: newPressureResource(s.Pressure.Memory),
+			IO:     newPressureResource(s.Pressure.IO),
+		},
+	}
+	if r.CPU.PerCore == nil {
+		r.CPU.PerCore = []float64{}
+	}
+	for _, d := range s.Disks {
+		r.Disks = append(r.Disks, Disk{
+			Path:              d.Path,
+			Device:            d.Device,
+			Fstype:            d.Fstype,
+			Total:             d.Total,
+			Used:              d.Used,
+			Free:              d.Free,
+			UsedPercent:       d.UsedPercent,
+			InodesUsedPercent: d.InodesUsedPercent,
+		})
+	}
+	for _, d := range s.DiskIO {
+		r.DiskIO = append(r.DiskIO, DiskIO{
+			Name:             d.Name,
+			ReadBytesPerSec:  d.ReadBytesPerSec,
+			WriteBytesPerSec: d.WriteBytesPerSec,
+			ReadOpsPerSec:    d.ReadOpsPerSec,
+			WriteOpsPerSec:   d.WriteOpsPerSec,
+			AwaitMs:          d.AwaitMs,
+			UtilPercent:      d.UtilPercent,
+		})
+	}
+	for _, i := range s.Interfaces {
+		r.Network.Interfaces = append(r.Network.Interfaces, Interface{Name: i.Name, BytesSentPerSec: i.BytesSentPerSec, BytesRecvPerSec: i.BytesRecvPerSec})
+	}
+	for _, p := range TopProcesses(s.Processes, opts.TopProcesses) {
+		r.Processes = append(r.Processes, NewProcess(p))
+	}
+	return r
+}
+
+func newPressureResource(p hundler.PressureResource) PressureResource {
+	line := func(l hundler.PressureLine) PressureLine {
+		return PressureLine{Avg10: l.Avg10, Avg60: l.Avg60, Avg300: l.Avg300, TotalSeconds: l.Total.Seconds()}
+	}
+	r := PressureResource{Available: p.Available, Some: line(p.Some)}
+	if p.HasFull {
+		full := line(p.Full)
+		r.Full = &full
+	}
+	return r
+}
+
+// NewProcess builds the record of one process.
+func NewProcess(p hundler.ProcessStat) Process {
+	return Process{
+		Pid:              p.Pid,
+		Name:             p.Name,
+		User:             p.Username,
+		State:            p.State,
+		Cmdline:          p.Cmdline,
+		CPUPercent:       p.CPUPercent,
+		MemoryBytes:      p.MemoryBytes,
+		ReadBytesPerSec:  p.ReadBytesPerSec,
+		WriteBytesPerSec: p.WriteBytesPerSec,
+		FDs:              p.NumFDs,
+	}
+}
+
+// TopProcesses returns the n processes using the most CPU, busiest first.
+func TopProcesses(procs []hundler.ProcessStat, n int) []hundler.ProcessStat {
+	if n <= 0 {
+		return nil
+	}
+	top := append([]hundler.ProcessStat(nil), procs...)
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].CPUPercent != top[j].CPUPercent {
+			return top[i].CPUPercent > top[j].CPUPercent
+		}
+		return top[i].Pid < top[j].Pid
+	})
+	return top[:min(n, len(top))]
+}
@@ -0,0 +1,42 @@
+package hundler
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readCgroup returns the cgroup of a process from <procRoot>/<pid>/cgroup.
+func readCgroup(procRoot string, pid int32) (string, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	return parseCgroup(string(data)), nil
+}
+
+// parseCgroup picks the cgroup path out of a /proc/<pid>/cgroup file. Lines have the
+// form "hierarchy-id:controllers:path". The unified (v2) hierarchy is preferred, then
+// the systemd hierarchy of a v1 setup, then the first hierarchy listed.
+func parseCgroup(data string) string {
+	var systemd, first string
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		switch {
+		case parts[0] == "0" && parts[1] == "":
+			return parts[2]
+		case parts[1] == "name=systemd":
+			systemd = parts[2]
+		case first == "":
+			first = parts[2]
+		}
+	}
+	if systemd != "" {
+		return systemd
+	}
+	return first
+}
@@ -0,0 +1,40 @@
+package hundler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCgroup(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"unified", "0::/user.slice/user-1000.slice/session-2.scope\n", "/user.slice/user-1000.slice/session-2.scope"},
+		{"hybrid", "12:cpu,cpuacct:/system.slice/nginx.service\n1:name=systemd:/system.slice/nginx.service\n0::/system.slice/nginx.service\n", "/system.slice/nginx.service"},
+		{"v1 systemd", "4:memory:/docker/abc\n1:name=systemd:/docker/abc/init\n", "/docker/abc/init"},
+		{"v1 only", "4:memory:/docker/abc\n3:cpu:/docker/def\n", "/docker/abc"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := parseCgroup(tt.data); got != tt.want {
+			t.Errorf("%s: parseCgroup() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadCgroup(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "42"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "42", "cgroup"), []byte("0::/init.scope\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readCgroup(root, 42); err != nil || got != "/init.scope" {
+		t.Errorf("readCgroup() = %q, %v, want /init.scope", got, err)
+	}
+	if _, err := readCgroup(root, 43); err == nil {
+		t.Error("readCgroup() of a missing process should fail")
+	}
+}
@@ -0,0 +1,165 @@
+package hundler
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// NamedSignal is a signal offered in the TUI signal picker.
+type NamedSignal struct {
+	Name   string
+	Signal syscall.Signal
+}
+
+// SignalSender delivers signals to processes.
+type SignalSender interface {
+	Signal(pid int32, sig syscall.Signal) error
+}
+
+// SignalSenderFunc adapts a function to SignalSender, e.g. to record signals in tests
+// instead of sending them.
+type SignalSenderFunc func(pid int32, sig syscall.Signal) error
+
+// Signal calls f(pid, sig).
+func (f SignalSenderFunc) Signal(pid int32, sig syscall.Signal) error {
+	return f(pid, sig)
+}
+
+// SystemSignalSender sends real signals through the operating system.
+type SystemSignalSender struct{}
+
+// Signal sends sig to the process with the given pid.
+func (SystemSignalSender) Signal(pid int32, sig syscall.Signal) error {
+	p, err := os.FindProcess(int(pid))
+	if err != nil {
+		return err
+	}
+	defer p.Release()
+	return p.Signal(sig)
+}
+
+// SignalResult is the outcome of signalling one process.
+type SignalResult struct {
+	Pid int32
+	Err error
+}
+
+// SignalAll sends sig to every pid and reports the outcome for each of them.
+// It carries on after a failure so one protected process doesn't stop the rest.
+func SignalAll(sender SignalSender, pids []int32, sig syscall.Signal) []SignalResult {
+	results := make([]SignalResult, len(pids))
+	for i, pid := range pids {
+		results[i] = SignalResult{Pid: pid, Err: sender.Signal(pid, sig)}
+	}
+	return results
+}
+
+// SignalName returns the conventional name of sig (e.g. "SIGTERM"), or its number
+// for signals that aren't in Signals.
+func SignalName(sig syscall.Signal) string {
+	for _, s := range Signals {
+		if s.Signal == sig {
+			return s.Name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
+
+// IOClass is an I/O scheduling class as used by ionice.
+type IOClass int
+
+const (
+	IOClassNone       IOClass = 0
+	IOClassRealtime   IOClass = 1
+	IOClassBestEffort IOClass = 2
+	IOClassIdle       IOClass = 3
+)
+
+var ioClassNames = map[string]IOClass{
+	"none":        IOClassNone,
+	"realtime":    IOClassRealtime,
+	"rt":          IOClassRealtime,
+	"best-effort": IOClassBestEffort,
+	"be":          IOClassBestEffort,
+	"idle":        IOClassIdle,
+}
+
+// ProcessController changes the scheduling of processes.
+type ProcessController interface {
+	// Renice sets the nice value (-20 to 19) of a process.
+	Renice(pid int32, nice int) error
+	// SetIOPriority sets the I/O class and, for realtime and best-effort, the level (0-7, 0 highest).
+	SetIOPriority(pid int32, class IOClass, level int) error
+	// SetAffinity restricts a process to the given logical CPUs.
+	SetAffinity(pid int32, cpus []int) error
+}
+
+// ParseIOPriority parses "class[:level]", e.g. "idle", "best-effort:7" or "rt:0".
+// Realtime and best-effort take a level that defaults to 4, the kernel's default for
+// best-effort. None and idle have no levels, and the kernel rejects one for them.
+func ParseIOPriority(s string) (IOClass, int, error) {
+	name, levelStr, hasLevel := strings.Cut(strings.TrimSpace(s), ":")
+	class, ok := ioClassNames[strings.ToLower(name)]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown I/O class %q (want none, realtime, best-effort or idle)", name)
+	}
+	if class == IOClassNone || class == IOClassIdle {
+		if hasLevel {
+			return 0, 0, fmt.Errorf("I/O class %q takes no level", name)
+		}
+		return class, 0, nil
+	}
+	level := 4
+	if hasLevel {
+		var err error
+		if level, err = strconv.Atoi(levelStr); err != nil || level < 0 || level > 7 {
+			return 0, 0, fmt.Errorf("invalid I/O priority level %q (want 0-7)", levelStr)
+		}
+	}
+	return class, level, nil
+}
+
+// maxCPUs is the number of CPUs an affinity mask can hold, the kernel's CPU_SETSIZE.
+const maxCPUs = 1024
+
+// ParseCPUList parses a CPU list such as "0-3,6" into sorted, de-duplicated CPU numbers.
+// CPU numbers must be below 1024, the size of an affinity mask.
+func ParseCPUList(s string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("invalid CPU %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU range %q", part)
+			}
+		}
+		if last >= maxCPUs {
+			return nil, fmt.Errorf("CPU %d out of range (want 0-%d)", last, maxCPUs-1)
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			seen[cpu] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("empty CPU list")
+	}
+	cpus := make([]int, 0, len(seen))
+	for cpu := range seen {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
@@ -0,0 +1,34 @@
+package hundler
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+// SystemProcessController changes process scheduling through Linux system calls.
+type SystemProcessController struct{}
+
+func (SystemProcessController) Renice(pid int32, nice int) error {
+	return unix.Setpriority(unix.PRIO_PROCESS, int(pid), nice)
+}
+
+func (SystemProcessController) SetIOPriority(pid int32, class IOClass, level int) error {
+	prio := int(class)<<ioprioClassShift | level
+	_, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(pid), uintptr(prio))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (SystemProcessController) SetAffinity(pid int32, cpus []int) error {
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+	return unix.SchedSetaffinity(int(pid), &set)
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package hundler
+
+import "errors"
+
+// SystemProcessController changes process scheduling. Only Linux is supported;
+// elsewhere every call fails with errors.ErrUnsupported.
+type SystemProcessController struct{}
+
+func (SystemProcessController) Renice(pid int32, nice int) error {
+	return errors.ErrUnsupported
+}
+
+func (SystemProcessController) SetIOPriority(pid int32, class IOClass, level int) error {
+	return errors.ErrUnsupported
+}
+
+func (SystemProcessController) SetAffinity(pid int32, cpus []int) error {
+	return errors.ErrUnsupported
+}
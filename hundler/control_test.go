@@ -0,0 +1,114 @@
+package hundler
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestSignalAll(t *testing.T) {
+	sent := make(map[int32]syscall.Signal)
+	sender := SignalSenderFunc(func(pid int32, sig syscall.Signal) error {
+		if pid == 1 {
+			return errors.New("operation not permitted")
+		}
+		sent[pid] = sig
+		return nil
+	})
+
+	results := SignalAll(sender, []int32{1, 200, 300}, syscall.SIGTERM)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Pid != 1 || results[0].Err == nil {
+		t.Errorf("signalling pid 1 should fail: %+v", results[0])
+	}
+	for _, pid := range []int32{200, 300} {
+		if sent[pid] != syscall.SIGTERM {
+			t.Errorf("pid %d should have received SIGTERM, got %v", pid, sent[pid])
+		}
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	if got := SignalName(syscall.SIGKILL); got != "SIGKILL" {
+		t.Errorf("SignalName(SIGKILL) = %q, want SIGKILL", got)
+	}
+	if got := SignalName(syscall.Signal(42)); got != "signal 42" {
+		t.Errorf("SignalName(42) = %q, want %q", got, "signal 42")
+	}
+}
+
+func TestParseIOPriority(t *testing.T) {
+	tests := []struct {
+		in    string
+		class IOClass
+		level int
+	}{
+		{"none", IOClassNone, 0},
+		{"idle", IOClassIdle, 0},
+		{"be", IOClassBestEffort, 4},
+		{"best-effort:7", IOClassBestEffort, 7},
+		{"RT:0", IOClassRealtime, 0},
+	}
+	for _, tt := range tests {
+		class, level, err := ParseIOPriority(tt.in)
+		if err != nil || class != tt.class || level != tt.level {
+			t.Errorf("ParseIOPriority(%q) = %v, %d, %v, want %v, %d", tt.in, class, level, err, tt.class, tt.level)
+		}
+	}
+	for _, in := range []string{"fast", "be:8", "be:x", "none:0", "idle:7"} {
+		if _, _, err := ParseIOPriority(in); err == nil {
+			t.Errorf("ParseIOPriority(%q) should fail", in)
+		}
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := ParseCPUList("4, 0-2,2")
+	if err != nil {
+		t.Fatalf("ParseCPUList error: %v", err)
+	}
+	want := []int{0, 1, 2, 4}
+	if len(cpus) != len(want) {
+		t.Fatalf("ParseCPUList = %v, want %v", cpus, want)
+	}
+	for i := range want {
+		if cpus[i] != want[i] {
+			t.Fatalf("ParseCPUList = %v, want %v", cpus, want)
+		}
+	}
+	if cpus, err := ParseCPUList("1020-1023"); err != nil || len(cpus) != 4 {
+		t.Errorf("ParseCPUList(\"1020-1023\") = %v, %v, want the last 4 CPUs of the mask", cpus, err)
+	}
+	for _, in := range []string{"", "a", "3-1", "-1", "1024", "0-1024", "0-4000000000", "99999999999999999999"} {
+		if _, err := ParseCPUList(in); err == nil {
+			t.Errorf("ParseCPUList(%q) should fail", in)
+		}
+	}
+}
+
+func TestSystemProcessControllerChild(t *testing.T) {
+	// Work on a child process so the test runner keeps its own priority.
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start a child process: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	// Lowering the I/O priority of our own child within best-effort never needs privileges.
+	c := SystemProcessController{}
+	for _, prio := range []string{"best-effort:7", "none"} {
+		class, level, err := ParseIOPriority(prio)
+		if err != nil {
+			t.Fatalf("ParseIOPriority(%q): %v", prio, err)
+		}
+		if err := c.SetIOPriority(int32(cmd.Process.Pid), class, level); err != nil && !errors.Is(err, errors.ErrUnsupported) {
+			t.Errorf("SetIOPriority(%s) on a child: %v", prio, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package hundler
 
 import (
 	"context"
+	"math"
+	"runtime"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
@@ -10,6 +12,67 @@ import (
 // CpuStat holds periodic CPU usage information
 type CpuStat struct {
 	Percent float64
+	PerCore []float64 // Usage percent of each logical CPU, in kernel order
+	Times   CpuTimes  // Share of the last interval spent in each time category
+}
+
+// CpuTimes holds the percentage of CPU time spent in each category over one sampling interval.
+// The categories sum to 100 together with Idle. On Linux, Guest and GuestNice are already
+// accounted for in User and Nice.
+type CpuTimes struct {
+	User      float64
+	System    float64
+	Idle      float64
+	Nice      float64
+	Iowait    float64
+	Irq       float64
+	Softirq   float64
+	Steal     float64
+	Guest     float64
+	GuestNice float64
+}
+
+// microseconds converts CPU seconds to the integer counters rateTracker works with.
+// Microseconds keep the precision of the clock-tick based times.
+func microseconds(seconds float64) uint64 {
+	return uint64(math.Round(seconds * 1e6))
+}
+
+// cpuTimesCounters lists the cpu.TimesStat fields tracked for rates in microseconds, in the
+// order cpuTimesPercent expects.
+func cpuTimesCounters(t cpu.TimesStat) []uint64 {
+	us := microseconds
+	return []uint64{us(t.User), us(t.System), us(t.Idle), us(t.Nice), us(t.Iowait), us(t.Irq),
+		us(t.Softirq), us(t.Steal), us(t.Guest), us(t.GuestNice)}
+}
+
+// cpuTimesPercent computes the per-category share of CPU time from the per-second rates of
+// the counters returned by cpuTimesCounters. It returns a zero value if no CPU time elapsed.
+func cpuTimesPercent(r []float64) CpuTimes {
+	total := 0.0
+	for _, v := range r {
+		total += v
+	}
+	if runtime.GOOS == "linux" {
+		// Guest time is already part of User and Nice.
+		total -= r[8] + r[9]
+	}
+	if total <= 0 {
+		return CpuTimes{}
+	}
+	pct := func(v float64) float64 { return v / total * 100 }
+	return CpuTimes{
+		User:      pct(r[0]),
+		System:    pct(r[1]),
+		Idle:      pct(r[2]),
+		Nice:      pct(r[3]),
+		Iowait:    pct(r[4]),
+		Irq:       pct(r[5]),
+		Softirq:   pct(r[6]),
+		Steal:     pct(r[7]),
+		Guest:     pct(r[8]),
+		GuestNice: pct(r[9]),
+	}
 }
 
 // StartCpuMonitor starts a goroutine that periodically sends CPU usage percent to the returned channel.
@@ -21,16 +84,29 @@ func StartCpuMonitor(ctx context.Context, interval time.Duration) <-chan CpuStat
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
+		// Read the times once up front so that the first sample has a baseline.
+		rates := newRateTracker()
+		if times, err := cpu.Times(false); err == nil && len(times) > 0 {
+			rates.update("cpu", time.Now(), cpuTimesCounters(times[0])...)
+		}
+
 		for {
 			// sample
+			var s CpuStat
 			percents, err := cpu.Percent(0, false)
-			var p float64
 			if err == nil && len(percents) > 0 {
-				p = percents[0]
+				s.Percent = percents[0]
+			}
+			if perCore, err := cpu.Percent(0, true); err == nil {
+				s.PerCore = perCore
+			}
+			if times, err := cpu.Times(false); err == nil && len(times) > 0 {
+				r, _, _ := rates.update("cpu", time.Now(), cpuTimesCounters(times[0])...)
+				s.Times = cpuTimesPercent(r)
 			}
 
 			select {
-			case ch <- CpuStat{Percent: p}:
+			case ch <- s:
 			case <-ctx.Done():
 				return
 			}
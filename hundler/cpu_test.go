@@ -2,8 +2,12 @@ package hundler
 
 import (
 	"context"
+	"math"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
 )
 
 func TestStartCpuMonitor(t *testing.T) {
@@ -19,6 +23,14 @@ func TestStartCpuMonitor(t *testing.T) {
 		if stat.Percent < 0 || stat.Percent > 100 {
 			t.Errorf("CpuStat.Percent out of range: %f", stat.Percent)
 		}
+		if len(stat.PerCore) == 0 {
+			t.Error("CpuStat.PerCore should not be empty")
+		}
+		for i, p := range stat.PerCore {
+			if p < 0 || p > 100 {
+				t.Errorf("CpuStat.PerCore[%d] out of range: %f", i, p)
+			}
+		}
 	case <-time.After(500 * time.Millisecond):
 		t.Fatal("Timeout waiting for CPU stats")
 	}
@@ -33,4 +45,50 @@ func TestStartCpuMonitor(t *testing.T) {
 	case <-time.After(500 * time.Millisecond):
 		// This is acceptable, as the goroutine might take a moment to shut down
 	}
-}
\ No newline at end of file
+}
+
+// cpuTimesBetween computes the CPU time breakdown between two samples taken two seconds apart.
+func cpuTimesBetween(t *testing.T, prev, cur cpu.TimesStat) CpuTimes {
+	t.Helper()
+	start := time.Now()
+	rates := newRateTracker()
+	rates.update("cpu", start, cpuTimesCounters(prev)...)
+	r, _, _ := rates.update("cpu", start.Add(2*time.Second), cpuTimesCounters(cur)...)
+	return cpuTimesPercent(r)
+}
+
+func TestCpuTimesPercent(t *testing.T) {
+	prev := cpu.TimesStat{User: 100, System: 50, Idle: 800, Iowait: 10, Steal: 0}
+	cur := cpu.TimesStat{User: 150, System: 60, Idle: 820, Iowait: 20, Steal: 10}
+
+	times := cpuTimesBetween(t, prev, cur)
+	want := CpuTimes{User: 50, System: 10, Idle: 20, Iowait: 10, Steal: 10}
+	if times != want {
+		t.Errorf("cpuTimesPercent() = %+v, want %+v", times, want)
+	}
+
+	if times := cpuTimesBetween(t, cur, cur); times != (CpuTimes{}) {
+		t.Errorf("cpuTimesPercent() with no elapsed time = %+v, want zero value", times)
+	}
+	// Counters going backwards, e.g. after a CPU went offline, re-baseline instead of spiking.
+	if times := cpuTimesBetween(t, cur, prev); times != (CpuTimes{}) {
+		t.Errorf("cpuTimesPercent() after a counter reset = %+v, want zero value", times)
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	// Guest time is part of user and nice time on Linux: 30 of the 60 user seconds and
+	// 5 of the 10 nice seconds were spent running guests.
+	prev = cpu.TimesStat{User: 100, Nice: 10, System: 50, Idle: 800}
+	cur = cpu.TimesStat{User: 160, Nice: 20, System: 70, Idle: 810, Guest: 30, GuestNice: 5}
+
+	times = cpuTimesBetween(t, prev, cur)
+	sum := times.User + times.Nice + times.System + times.Idle + times.Iowait + times.Irq + times.Softirq + times.Steal
+	if math.Abs(sum-100) > 1e-9 {
+		t.Errorf("non-guest categories sum to %v, want 100: %+v", sum, times)
+	}
+	if math.Abs(times.User-60) > 1e-9 || math.Abs(times.Guest-30) > 1e-9 || math.Abs(times.GuestNice-5) > 1e-9 {
+		t.Errorf("cpuTimesPercent() = %+v, want User 60, Guest 30, GuestNice 5", times)
+	}
+}
@@ -2,6 +2,9 @@ package hundler
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/disk"
@@ -9,28 +12,137 @@ import (
 
 type DiskStat struct {
 	Path        string
+	Device      string
+	Fstype      string
 	Total       uint64
 	Used        uint64
+	Free        uint64
 	UsedPercent float64
+
+	InodesTotal       uint64
+	InodesUsed        uint64
+	InodesFree        uint64
+	InodesUsedPercent float64
+}
+
+// DiskThresholds holds the usage percentages at which a filesystem is considered in trouble.
+// A zero threshold disables that check.
+type DiskThresholds struct {
+	UsedPercent       float64
+	InodesUsedPercent float64
 }
 
-// StartDiskMonitor streams usage for a given path (e.g. "/") periodically.
-func StartDiskMonitor(ctx context.Context, interval time.Duration, path string) <-chan DiskStat {
-	ch := make(chan DiskStat)
+// Alerts returns a human readable message for every threshold the filesystem has reached.
+func (d DiskStat) Alerts(t DiskThresholds) []string {
+	var alerts []string
+	if t.UsedPercent > 0 && d.UsedPercent >= t.UsedPercent {
+		alerts = append(alerts, fmt.Sprintf("%s space %.1f%% used (threshold %.0f%%)", d.Path, d.UsedPercent, t.UsedPercent))
+	}
+	if t.InodesUsedPercent > 0 && d.InodesTotal > 0 && d.InodesUsedPercent >= t.InodesUsedPercent {
+		alerts = append(alerts, fmt.Sprintf("%s inodes %.1f%% used (threshold %.0f%%)", d.Path, d.InodesUsedPercent, t.InodesUsedPercent))
+	}
+	return alerts
+}
+
+// DefaultIgnoredFsTypes lists pseudo and virtual filesystems skipped when discovering mounts.
+var DefaultIgnoredFsTypes = []string{
+	"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs", "devpts",
+	"devtmpfs", "efivarfs", "fusectl", "fuse.lxcfs", "hugetlbfs", "mqueue", "nsfs", "overlay",
+	"proc", "pstore", "ramfs", "rpc_pipefs", "securityfs", "selinuxfs", "squashfs", "sysfs",
+	"tmpfs", "tracefs",
+}
+
+// DiskOptions selects which filesystems StartDiskMonitor reports.
+type DiskOptions struct {
+	Paths         []string // Paths to report explicitly (e.g. "/", "/var")
+	Discover      bool     // Also report every real mounted filesystem
+	IgnoreFsTypes []string // Filesystem types skipped during discovery, as glob patterns
+}
+
+// discoverMounts returns the mount points worth reporting, skipping ignored filesystem
+// types and bind mounts of a device that is already listed.
+func discoverMounts(parts []disk.PartitionStat, ignoreFsTypes []string) []disk.PartitionStat {
+	var mounts []disk.PartitionStat
+	seenMount := make(map[string]bool)
+	seenDevice := make(map[string]bool)
+	for _, p := range parts {
+		if !matchName(p.Fstype, nil, ignoreFsTypes) || seenMount[p.Mountpoint] {
+			continue
+		}
+		if strings.HasPrefix(p.Device, "/dev/") {
+			if seenDevice[p.Device] {
+				continue
+			}
+			seenDevice[p.Device] = true
+		}
+		seenMount[p.Mountpoint] = true
+		mounts = append(mounts, p)
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Mountpoint < mounts[j].Mountpoint })
+	return mounts
+}
+
+// diskTargets merges the explicit paths with the discovered mounts, keeping explicit paths first.
+func diskTargets(ctx context.Context, opts DiskOptions) []disk.PartitionStat {
+	var targets []disk.PartitionStat
+	seen := make(map[string]bool)
+	for _, p := range opts.Paths {
+		if !seen[p] {
+			seen[p] = true
+			targets = append(targets, disk.PartitionStat{Mountpoint: p})
+		}
+	}
+	if opts.Discover {
+		if parts, err := disk.PartitionsWithContext(ctx, true); err == nil {
+			for _, p := range discoverMounts(parts, opts.IgnoreFsTypes) {
+				if !seen[p.Mountpoint] {
+					seen[p.Mountpoint] = true
+					targets = append(targets, p)
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// StartDiskMonitor streams usage for the configured paths (e.g. "/") and, optionally,
+// every discovered mount point periodically.
+func StartDiskMonitor(ctx context.Context, interval time.Duration, opts DiskOptions) <-chan []DiskStat {
+	ch := make(chan []DiskStat)
 	go func() {
 		defer close(ch)
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
-			u, err := disk.Usage(path)
-			var s DiskStat
-			if err == nil {
-				s = DiskStat{Path: path, Total: u.Total, Used: u.Used, UsedPercent: u.UsedPercent}
+			var stats []DiskStat
+			for _, t := range diskTargets(ctx, opts) {
+				u, err := disk.UsageWithContext(ctx, t.Mountpoint)
+				if err != nil {
+					continue
+				}
+				fstype := t.Fstype
+				if fstype == "" {
+					fstype = u.Fstype
+				}
+				stats = append(stats, DiskStat{
+					Path:        t.Mountpoint,
+					Device:      t.Device,
+					Fstype:      fstype,
+					Total:       u.Total,
+					Used:        u.Used,
+					Free:        u.Free,
+					UsedPercent: u.UsedPercent,
+
+					InodesTotal:       u.InodesTotal,
+					InodesUsed:        u.InodesUsed,
+					InodesFree:        u.InodesFree,
+					InodesUsedPercent: u.InodesUsedPercent,
+				})
 			}
 
 			select {
-			case ch <- s:
+			case ch <- stats:
 			case <-ctx.Done():
 				return
 			}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
 )
 
 func TestStartDiskMonitor(t *testing.T) {
@@ -12,17 +14,24 @@ func TestStartDiskMonitor(t *testing.T) {
 
 	interval := 100 * time.Millisecond
 	path := "/" // Test root path
-	diskCh := StartDiskMonitor(ctx, interval, path)
+	diskCh := StartDiskMonitor(ctx, interval, DiskOptions{Paths: []string{path}})
 
 	// Test if at least one value is received
 	select {
-	case stat := <-diskCh:
+	case stats := <-diskCh:
+		if len(stats) != 1 {
+			t.Fatalf("expected 1 DiskStat, got %d", len(stats))
+		}
+		stat := stats[0]
 		if stat.Total == 0 {
 			t.Errorf("DiskStat.Total should not be 0")
 		}
 		if stat.UsedPercent < 0 || stat.UsedPercent > 100 {
 			t.Errorf("DiskStat.UsedPercent out of range: %f", stat.UsedPercent)
 		}
+		if stat.InodesTotal > 0 && stat.InodesUsed+stat.InodesFree > stat.InodesTotal {
+			t.Errorf("DiskStat inodes used (%d) + free (%d) exceed total (%d)", stat.InodesUsed, stat.InodesFree, stat.InodesTotal)
+		}
 		if stat.Path != path {
 			t.Errorf("DiskStat.Path mismatch: got %s, want %s", stat.Path, path)
 		}
@@ -40,4 +49,54 @@ func TestStartDiskMonitor(t *testing.T) {
 	case <-time.After(500 * time.Millisecond):
 		// This is acceptable
 	}
-}
\ No newline at end of file
+}
+
+func TestDiscoverMounts(t *testing.T) {
+	parts := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+		{Device: "proc", Mountpoint: "/proc", Fstype: "proc"},
+		{Device: "tmpfs", Mountpoint: "/run", Fstype: "tmpfs"},
+		{Device: "/dev/sda2", Mountpoint: "/home", Fstype: "xfs"},
+		{Device: "/dev/sda2", Mountpoint: "/srv/bind", Fstype: "xfs"},
+		{Device: "/dev/loop0", Mountpoint: "/snap/core/1", Fstype: "squashfs"},
+		{Device: "lxcfs", Mountpoint: "/var/lib/lxcfs", Fstype: "fuse.lxcfs"},
+	}
+
+	mounts := discoverMounts(parts, DefaultIgnoredFsTypes)
+	var got []string
+	for _, m := range mounts {
+		got = append(got, m.Mountpoint)
+	}
+	want := []string{"/", "/home"}
+	if len(got) != len(want) {
+		t.Fatalf("discoverMounts() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("discoverMounts()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDiskStatAlerts(t *testing.T) {
+	d := DiskStat{Path: "/var", UsedPercent: 40, InodesTotal: 1000, InodesUsed: 980, InodesUsedPercent: 98}
+
+	alerts := d.Alerts(DiskThresholds{UsedPercent: 90, InodesUsedPercent: 95})
+	if len(alerts) != 1 {
+		t.Fatalf("expected only the inode alert, got %v", alerts)
+	}
+
+	if alerts := d.Alerts(DiskThresholds{UsedPercent: 30}); len(alerts) != 1 {
+		t.Errorf("expected only the space alert, got %v", alerts)
+	}
+
+	if alerts := d.Alerts(DiskThresholds{}); len(alerts) != 0 {
+		t.Errorf("zero thresholds should disable alerts, got %v", alerts)
+	}
+
+	// Filesystems without inodes (e.g. btrfs, vfat) never raise an inode alert.
+	noInodes := DiskStat{Path: "/boot/efi"}
+	if alerts := noInodes.Alerts(DiskThresholds{InodesUsedPercent: 1}); len(alerts) != 0 {
+		t.Errorf("filesystem without inodes should not alert, got %v", alerts)
+	}
+}
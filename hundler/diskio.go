@@ -0,0 +1,107 @@
+package hundler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// DiskIOStat holds the throughput, IOPS, latency and utilisation of one block device over one interval,
+// along with the cumulative counters they are computed from.
+type DiskIOStat struct {
+	Name             string
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	ReadOpsPerSec    float64
+	WriteOpsPerSec   float64
+	AwaitMs          float64 // Average time per completed request, including queueing
+	UtilPercent      float64 // Share of the interval the device had requests in flight
+
+	TotalReadBytes  uint64
+	TotalWriteBytes uint64
+	TotalReads      uint64        // Completed reads
+	TotalWrites     uint64        // Completed writes
+	TotalReadTime   time.Duration // Time spent on reads, including queueing
+	TotalWriteTime  time.Duration // Time spent on writes, including queueing
+	TotalIoTime     time.Duration // Time the device had requests in flight
+}
+
+// diskIOCounters lists the disk.IOCounters fields tracked for rates, in the order diskIOStat expects.
+func diskIOCounters(c disk.IOCountersStat) []uint64 {
+	return []uint64{c.ReadBytes, c.WriteBytes, c.ReadCount, c.WriteCount, c.ReadTime, c.WriteTime, c.IoTime}
+}
+
+// diskIOStat derives throughput, IOPS, await and utilisation from the per-second rates of
+// the counters returned by diskIOCounters.
+func diskIOStat(name string, c disk.IOCountersStat, r []float64) DiskIOStat {
+	s := DiskIOStat{
+		Name:             name,
+		ReadBytesPerSec:  r[0],
+		WriteBytesPerSec: r[1],
+		ReadOpsPerSec:    r[2],
+		WriteOpsPerSec:   r[3],
+		TotalReadBytes:   c.ReadBytes,
+		TotalWriteBytes:  c.WriteBytes,
+		TotalReads:       c.ReadCount,
+		TotalWrites:      c.WriteCount,
+		TotalReadTime:    time.Duration(c.ReadTime) * time.Millisecond,
+		TotalWriteTime:   time.Duration(c.WriteTime) * time.Millisecond,
+		TotalIoTime:      time.Duration(c.IoTime) * time.Millisecond,
+	}
+	if ops := r[2] + r[3]; ops > 0 {
+		// Milliseconds spent on requests per second, divided by requests per second.
+		s.AwaitMs = (r[4] + r[5]) / ops
+	}
+	// IoTime grows by at most 1000ms per second while the device is busy.
+	s.UtilPercent = r[6] / 1000 * 100
+	if s.UtilPercent > 100 {
+		s.UtilPercent = 100
+	}
+	return s
+}
+
+// StartDiskIOMonitor streams per-device I/O rates computed from disk.IOCounters deltas.
+// Devices are filtered with the include/exclude glob lists (e.g. "sd*", "loop*").
+func StartDiskIOMonitor(ctx context.Context, interval time.Duration, include, exclude []string) <-chan []DiskIOStat {
+	ch := make(chan []DiskIOStat)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		rates := newRateTracker()
+
+		for {
+			var stats []DiskIOStat
+			counters, err := disk.IOCountersWithContext(ctx)
+			now := time.Now()
+			if err == nil {
+				for name, c := range counters {
+					if !matchName(name, include, exclude) {
+						continue
+					}
+					r, _, _ := rates.update(name, now, diskIOCounters(c)...)
+					stats = append(stats, diskIOStat(name, c, r))
+				}
+				rates.prune()
+				sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+			}
+
+			select {
+			case ch <- stats:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,74 @@
+package hundler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+func TestDiskIOStat(t *testing.T) {
+	prev := disk.IOCountersStat{Name: "sda", ReadCount: 100, WriteCount: 50, ReadBytes: 1000, WriteBytes: 2000, ReadTime: 10, WriteTime: 20, IoTime: 100}
+	cur := disk.IOCountersStat{Name: "sda", ReadCount: 120, WriteCount: 70, ReadBytes: 5000, WriteBytes: 4000, ReadTime: 50, WriteTime: 60, IoTime: 600}
+
+	start := time.Now()
+	rates := newRateTracker()
+	rates.update("sda", start, diskIOCounters(prev)...)
+	r, _, ok := rates.update("sda", start.Add(2*time.Second), diskIOCounters(cur)...)
+	if !ok {
+		t.Fatal("expected rates for the second sample")
+	}
+
+	got := diskIOStat("sda", cur, r)
+	want := DiskIOStat{
+		Name:             "sda",
+		ReadBytesPerSec:  2000,
+		WriteBytesPerSec: 1000,
+		ReadOpsPerSec:    10,
+		WriteOpsPerSec:   10,
+		AwaitMs:          2,
+		UtilPercent:      25,
+		TotalReadBytes:   5000,
+		TotalWriteBytes:  4000,
+		TotalReads:       120,
+		TotalWrites:      70,
+		TotalReadTime:    50 * time.Millisecond,
+		TotalWriteTime:   60 * time.Millisecond,
+		TotalIoTime:      600 * time.Millisecond,
+	}
+	if got != want {
+		t.Errorf("diskIOStat() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStartDiskIOMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 100 * time.Millisecond
+	ioCh := StartDiskIOMonitor(ctx, interval, nil, nil)
+
+	// Test if at least one value is received
+	select {
+	case stats := <-ioCh:
+		for _, s := range stats {
+			if s.UtilPercent < 0 || s.UtilPercent > 100 {
+				t.Errorf("DiskIOStat.UtilPercent out of range for %s: %f", s.Name, s.UtilPercent)
+			}
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Timeout waiting for Disk IO stats")
+	}
+
+	// Test if monitoring stops after context cancellation
+	cancel()
+	select {
+	case _, ok := <-ioCh:
+		if ok {
+			t.Error("Disk IO channel should be closed after context cancellation")
+		}
+	case <-time.After(500 * time.Millisecond):
+		// This is acceptable
+	}
+}
@@ -0,0 +1,22 @@
+package hundler
+
+import "path"
+
+// matchName reports whether name passes the include and exclude glob lists (path.Match syntax).
+// An empty include list matches every name; exclude patterns always win.
+func matchName(name string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package hundler
+
+import "testing"
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             bool
+	}{
+		{"sda", nil, nil, true},
+		{"loop0", nil, []string{"loop*"}, false},
+		{"sda", []string{"sd*", "nvme*"}, nil, true},
+		{"dm-0", []string{"sd*", "nvme*"}, nil, false},
+		{"sdb", []string{"sd*"}, []string{"sdb"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchName(tt.name, tt.include, tt.exclude); got != tt.want {
+			t.Errorf("matchName(%q, %v, %v) = %v, want %v", tt.name, tt.include, tt.exclude, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package hundler
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
+)
+
+// LoadStat holds the system load averages, run-queue counts and uptime.
+type LoadStat struct {
+	Load1        float64
+	Load5        float64
+	Load15       float64
+	Cores        int // Logical CPUs the load is spread over, 0 if unknown
+	ProcsRunning int
+	ProcsBlocked int
+	BootTime     time.Time
+	Uptime       time.Duration
+}
+
+// StartLoadMonitor starts a goroutine that periodically sends load average, run-queue and uptime information.
+// The channel is closed when the provided context is cancelled.
+func StartLoadMonitor(ctx context.Context, interval time.Duration) <-chan LoadStat {
+	ch := make(chan LoadStat)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			var s LoadStat
+			if avg, err := load.AvgWithContext(ctx); err == nil {
+				s.Load1, s.Load5, s.Load15 = avg.Load1, avg.Load5, avg.Load15
+			}
+			if cores, err := cpu.CountsWithContext(ctx, true); err == nil {
+				s.Cores = cores
+			}
+			if misc, err := load.MiscWithContext(ctx); err == nil {
+				s.ProcsRunning = misc.ProcsRunning
+				s.ProcsBlocked = misc.ProcsBlocked
+			}
+			if boot, err := host.BootTimeWithContext(ctx); err == nil {
+				s.BootTime = time.Unix(int64(boot), 0)
+				s.Uptime = time.Since(s.BootTime).Truncate(time.Second)
+			}
+
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
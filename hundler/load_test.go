@@ -0,0 +1,45 @@
+package hundler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartLoadMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 100 * time.Millisecond
+	loadCh := StartLoadMonitor(ctx, interval)
+
+	// Test if at least one value is received
+	select {
+	case stat := <-loadCh:
+		if stat.Load1 < 0 || stat.Load5 < 0 || stat.Load15 < 0 {
+			t.Errorf("LoadStat load averages should not be negative: %+v", stat)
+		}
+		if stat.Cores <= 0 {
+			t.Errorf("LoadStat.Cores should be positive: %d", stat.Cores)
+		}
+		if stat.BootTime.IsZero() {
+			t.Error("LoadStat.BootTime should be set")
+		}
+		if stat.Uptime <= 0 {
+			t.Errorf("LoadStat.Uptime should be positive: %v", stat.Uptime)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Timeout waiting for Load stats")
+	}
+
+	// Test if monitoring stops after context cancellation
+	cancel()
+	select {
+	case _, ok := <-loadCh:
+		if ok {
+			t.Error("Load channel should be closed after context cancellation")
+		}
+	case <-time.After(500 * time.Millisecond):
+		// This is acceptable
+	}
+}
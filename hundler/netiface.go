@@ -0,0 +1,103 @@
+package hundler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// InterfaceStat holds the traffic, packet, error and drop rates (per second) of one network interface.
+// The Total fields are the counts since boot, as exported to Prometheus.
+type InterfaceStat struct {
+	Name              string
+	BytesSentPerSec   float64
+	BytesRecvPerSec   float64
+	PacketsSentPerSec float64
+	PacketsRecvPerSec float64
+	ErrinPerSec       float64
+	ErroutPerSec      float64
+	DropinPerSec      float64
+	DropoutPerSec     float64
+	TotalBytesSent    uint64
+	TotalBytesRecv    uint64
+	TotalPacketsSent  uint64
+	TotalPacketsRecv  uint64
+	TotalErrin        uint64
+	TotalErrout       uint64
+	TotalDropin       uint64
+	TotalDropout      uint64
+}
+
+// interfaceCounters lists the net.IOCounters fields tracked for rates, in the order interfaceStat expects.
+func interfaceCounters(c net.IOCountersStat) []uint64 {
+	return []uint64{c.BytesSent, c.BytesRecv, c.PacketsSent, c.PacketsRecv, c.Errin, c.Errout, c.Dropin, c.Dropout}
+}
+
+// interfaceStat builds an InterfaceStat from the counters of an interface and their per-second rates.
+func interfaceStat(c net.IOCountersStat, r []float64) InterfaceStat {
+	return InterfaceStat{
+		Name:              c.Name,
+		BytesSentPerSec:   r[0],
+		BytesRecvPerSec:   r[1],
+		PacketsSentPerSec: r[2],
+		PacketsRecvPerSec: r[3],
+		ErrinPerSec:       r[4],
+		ErroutPerSec:      r[5],
+		DropinPerSec:      r[6],
+		DropoutPerSec:     r[7],
+		TotalBytesSent:    c.BytesSent,
+		TotalBytesRecv:    c.BytesRecv,
+		TotalPacketsSent:  c.PacketsSent,
+		TotalPacketsRecv:  c.PacketsRecv,
+		TotalErrin:        c.Errin,
+		TotalErrout:       c.Errout,
+		TotalDropin:       c.Dropin,
+		TotalDropout:      c.Dropout,
+	}
+}
+
+// StartInterfaceMonitor streams per-interface rates for every network interface.
+// Interfaces are filtered with the include/exclude glob lists (e.g. "eth*", "veth*").
+func StartInterfaceMonitor(ctx context.Context, interval time.Duration, include, exclude []string) <-chan []InterfaceStat {
+	ch := make(chan []InterfaceStat)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		rates := newRateTracker()
+
+		for {
+			var stats []InterfaceStat
+			counters, err := net.IOCountersWithContext(ctx, true)
+			now := time.Now()
+			if err == nil {
+				for _, c := range counters {
+					if !matchName(c.Name, include, exclude) {
+						continue
+					}
+					r, _, _ := rates.update(c.Name, now, interfaceCounters(c)...)
+					stats = append(stats, interfaceStat(c, r))
+				}
+				rates.prune()
+				sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+			}
+
+			select {
+			case ch <- stats:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
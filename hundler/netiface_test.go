@@ -0,0 +1,72 @@
+package hundler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+func TestInterfaceStat(t *testing.T) {
+	prev := net.IOCountersStat{Name: "eth0", BytesSent: 1000, BytesRecv: 2000, PacketsSent: 10, PacketsRecv: 20, Errin: 0, Dropout: 5}
+	cur := net.IOCountersStat{Name: "eth0", BytesSent: 3000, BytesRecv: 8000, PacketsSent: 30, PacketsRecv: 80, Errin: 4, Dropout: 5}
+
+	start := time.Now()
+	rates := newRateTracker()
+	rates.update("eth0", start, interfaceCounters(prev)...)
+	r, _, ok := rates.update("eth0", start.Add(2*time.Second), interfaceCounters(cur)...)
+	if !ok {
+		t.Fatal("expected rates for the second sample")
+	}
+
+	got := interfaceStat(cur, r)
+	want := InterfaceStat{
+		Name:              "eth0",
+		BytesSentPerSec:   1000,
+		BytesRecvPerSec:   3000,
+		PacketsSentPerSec: 10,
+		PacketsRecvPerSec: 30,
+		ErrinPerSec:       2,
+		TotalBytesSent:    3000,
+		TotalBytesRecv:    8000,
+		TotalPacketsSent:  30,
+		TotalPacketsRecv:  80,
+		TotalErrin:        4,
+		TotalDropout:      5,
+	}
+	if got != want {
+		t.Errorf("interfaceStat() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStartInterfaceMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 100 * time.Millisecond
+	ifaceCh := StartInterfaceMonitor(ctx, interval, nil, []string{"lo"})
+
+	// Test if at least one value is received
+	select {
+	case stats := <-ifaceCh:
+		for _, s := range stats {
+			if s.Name == "lo" {
+				t.Error("excluded interface 'lo' should not be reported")
+			}
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Timeout waiting for Interface stats")
+	}
+
+	// Test if monitoring stops after context cancellation
+	cancel()
+	select {
+	case _, ok := <-ifaceCh:
+		if ok {
+			t.Error("Interface channel should be closed after context cancellation")
+		}
+	case <-time.After(500 * time.Millisecond):
+		// This is acceptable
+	}
+}
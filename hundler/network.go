@@ -15,6 +15,21 @@ type NetStat struct {
 	TotalBytesRecv  uint64
 }
 
+// readNetCounters returns the IO counters of the named interface, or the sum over all
+// interfaces when ifaceName is empty. found is false if the interface doesn't exist.
+func readNetCounters(ctx context.Context, ifaceName string) (c net.IOCountersStat, found bool, err error) {
+	counters, err := net.IOCountersWithContext(ctx, ifaceName != "")
+	if err != nil {
+		return c, false, err
+	}
+	for _, c := range counters {
+		if ifaceName == "" || c.Name == ifaceName {
+			return c, true, nil
+		}
+	}
+	return c, false, nil
+}
+
 // StartNetworkMonitor streams network byte rates (per-second) computed from IO counters.
 func StartNetworkMonitor(ctx context.Context, interval time.Duration, ifaceName string) <-chan NetStat {
 	ch := make(chan NetStat)
@@ -23,73 +38,24 @@ func StartNetworkMonitor(ctx context.Context, interval time.Duration, ifaceName
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		var prevSent uint64
-		var prevRecv uint64
-
-		// Initial read to set prevSent and prevRecv
-		if ifaceName != "" {
-			if counters, err := net.IOCounters(true); err == nil {
-				for _, c := range counters {
-					if c.Name == ifaceName {
-						prevSent = c.BytesSent
-						prevRecv = c.BytesRecv
-						break
-					}
-				}
-			}
-		} else {
-			if counters, err := net.IOCounters(false); err == nil && len(counters) > 0 {
-				prevSent = counters[0].BytesSent
-				prevRecv = counters[0].BytesRecv
-			}
-		}
+		rates := newRateTracker()
 
 		for {
-			var curSent uint64
-			var curRecv uint64
-			var err error
-
-			if ifaceName != "" {
-				var found bool
-				var counters []net.IOCountersStat
-				counters, err = net.IOCounters(true)
-				if err == nil {
-					for _, c := range counters {
-						if c.Name == ifaceName {
-							curSent = c.BytesSent
-							curRecv = c.BytesRecv
-							found = true
-							break
-						}
-					}
-				}
-				if !found && err == nil {
-					// Interface not found, perhaps it was removed or never existed
-					// Log this or send an error state if needed
-					log.Printf("Network interface '%s' not found.", ifaceName)
-				}
-			} else {
-				var counters []net.IOCountersStat
-				counters, err = net.IOCounters(false)
-				if err == nil && len(counters) > 0 {
-					curSent = counters[0].BytesSent
-					curRecv = counters[0].BytesRecv
-				}
-			}
-
 			var s NetStat
-			if err == nil {
-				elapsed := interval.Seconds()
-				if prevSent > 0 {
-					s.BytesSentPerSec = float64(curSent-prevSent) / elapsed
-				}
-				if prevRecv > 0 {
-					s.BytesRecvPerSec = float64(curRecv-prevRecv) / elapsed
+			c, found, err := readNetCounters(ctx, ifaceName)
+			now := time.Now()
+			if err == nil && !found {
+				// Interface not found, perhaps it was removed or never existed
+				log.Printf("Network interface '%s' not found.", ifaceName)
+			}
+			if found {
+				r, _, _ := rates.update(c.Name, now, c.BytesSent, c.BytesRecv)
+				s = NetStat{
+					BytesSentPerSec: r[0],
+					BytesRecvPerSec: r[1],
+					TotalBytesSent:  c.BytesSent,
+					TotalBytesRecv:  c.BytesRecv,
 				}
-				s.TotalBytesSent = curSent
-				s.TotalBytesRecv = curRecv
-				prevSent = curSent
-				prevRecv = curRecv
 			}
 
 			select {
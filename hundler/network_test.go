@@ -11,7 +11,7 @@ func TestStartNetworkMonitor(t *testing.T) {
 	defer cancel()
 
 	interval := 100 * time.Millisecond
-	netCh := StartNetworkMonitor(ctx, interval)
+	netCh := StartNetworkMonitor(ctx, interval, "")
 
 	// Test if at least one value is received
 	select {
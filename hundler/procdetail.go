@@ -0,0 +1,307 @@
+package hundler
+
+import (
+	"context"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ProcessDetail holds everything shown in the detail view of a single process.
+// It is far more expensive to collect than ProcessStat, so it is only gathered
+// for one process at a time, on demand.
+type ProcessDetail struct {
+	Pid       int32
+	Name      string
+	Cmdline   string
+	Cwd       string
+	Exe       string
+	Environ   []string
+	OpenFiles []OpenFile
+	Sockets   []Socket
+	Memory    MemoryMapsSummary
+	Limits    []ProcessLimit
+	Threads   []ThreadStat // Ordered by CPU usage, busiest first
+	Cgroup    string
+
+	Unavailable DetailSection // Sections that couldn't be read, e.g. due to missing permissions
+}
+
+// DetailSection identifies a part of ProcessDetail that may be unavailable.
+type DetailSection uint32
+
+const (
+	SectionCmdline DetailSection = 1 << iota // Cmdline
+	SectionCwd                               // Cwd
+	SectionExe                               // Exe
+	SectionEnviron                           // Environ
+	SectionFiles                             // OpenFiles
+	SectionSockets                           // Sockets
+	SectionMemory                            // Memory
+	SectionLimits                            // Limits
+	SectionThreads                           // Threads
+	SectionCgroup                            // Cgroup
+)
+
+// Available reports whether every section in s could be read.
+func (d ProcessDetail) Available(s DetailSection) bool {
+	return d.Unavailable&s == 0
+}
+
+// OpenFile is an open file descriptor and what it points to, e.g. a path or "pipe:[1234]".
+type OpenFile struct {
+	Fd   uint64
+	Path string
+}
+
+// Socket is an open network or unix socket.
+type Socket struct {
+	Fd     uint32
+	Type   string // tcp, tcp6, udp, udp6 or unix
+	Local  string
+	Remote string
+	Status string // e.g. LISTEN or ESTABLISHED; empty for connectionless sockets
+}
+
+// MemoryMapsSummary sums up the memory mappings of a process.
+type MemoryMapsSummary struct {
+	Mappings int
+	Size     uint64 // Bytes of address space mapped
+	Rss      uint64
+	Pss      uint64 // Proportional set size: shared pages divided among their users
+	Swap     uint64
+	Top      []MemoryMapping // Largest mappings by RSS, combined by path
+}
+
+// MemoryMapping is the memory mapped from one path, or anonymous memory when Path is empty.
+type MemoryMapping struct {
+	Path string
+	Size uint64
+	Rss  uint64
+}
+
+// ProcessLimit is a resource limit as listed in /proc/<pid>/limits.
+type ProcessLimit struct {
+	Name string
+	Soft uint64 // RlimitInfinity when unlimited
+	Hard uint64
+	Used uint64 // Current usage where it can be determined, otherwise 0
+}
+
+// RlimitInfinity is the value of a limit that is not set.
+const RlimitInfinity = math.MaxUint64
+
+// ThreadStat is one thread of a process.
+type ThreadStat struct {
+	Tid        int32
+	Name       string
+	CPUPercent float64 // CPU usage over the last refresh; 100 is one full core
+}
+
+// topMappings is the number of mappings listed in MemoryMapsSummary.Top.
+const topMappings = 5
+
+var rlimitNames = map[int32]string{
+	process.RLIMIT_CPU:        "cpu",
+	process.RLIMIT_FSIZE:      "fsize",
+	process.RLIMIT_DATA:       "data",
+	process.RLIMIT_STACK:      "stack",
+	process.RLIMIT_CORE:       "core",
+	process.RLIMIT_RSS:        "rss",
+	process.RLIMIT_NPROC:      "nproc",
+	process.RLIMIT_NOFILE:     "nofile",
+	process.RLIMIT_MEMLOCK:    "memlock",
+	process.RLIMIT_AS:         "as",
+	process.RLIMIT_LOCKS:      "locks",
+	process.RLIMIT_SIGPENDING: "sigpending",
+	process.RLIMIT_MSGQUEUE:   "msgqueue",
+	process.RLIMIT_NICE:       "nice",
+	process.RLIMIT_RTPRIO:     "rtprio",
+	process.RLIMIT_RTTIME:     "rttime",
+}
+
+// detailCollector gathers ProcessDetail for one process, remembering thread CPU times
+// between refreshes to compute per-thread CPU usage.
+type detailCollector struct {
+	proc     *process.Process
+	procRoot string
+	now      func() time.Time
+	threads  *rateTracker
+}
+
+func (c *detailCollector) collect(ctx context.Context) ProcessDetail {
+	p := c.proc
+	d := ProcessDetail{Pid: p.Pid}
+	d.Name, _ = p.NameWithContext(ctx)
+	var err error
+	if d.Cmdline, err = p.CmdlineWithContext(ctx); err != nil {
+		d.Unavailable |= SectionCmdline
+	}
+	if d.Cwd, err = p.CwdWithContext(ctx); err != nil {
+		d.Unavailable |= SectionCwd
+	}
+	if d.Exe, err = p.ExeWithContext(ctx); err != nil {
+		d.Unavailable |= SectionExe
+	}
+	if env, err := p.EnvironWithContext(ctx); err == nil {
+		for _, e := range env {
+			if e != "" {
+				d.Environ = append(d.Environ, e)
+			}
+		}
+	} else {
+		d.Unavailable |= SectionEnviron
+	}
+	if files, err := p.OpenFilesWithContext(ctx); err == nil {
+		for _, f := range files {
+			d.OpenFiles = append(d.OpenFiles, OpenFile{Fd: f.Fd, Path: f.Path})
+		}
+		sort.Slice(d.OpenFiles, func(i, j int) bool { return d.OpenFiles[i].Fd < d.OpenFiles[j].Fd })
+	} else {
+		d.Unavailable |= SectionFiles
+	}
+	if conns, err := p.ConnectionsWithContext(ctx); err == nil {
+		for _, conn := range conns {
+			d.Sockets = append(d.Sockets, Socket{
+				Fd:     conn.Fd,
+				Type:   socketType(conn.Family, conn.Type),
+				Local:  socketAddr(conn.Family, conn.Laddr.IP, conn.Laddr.Port),
+				Remote: socketAddr(conn.Family, conn.Raddr.IP, conn.Raddr.Port),
+				Status: socketStatus(conn.Status),
+			})
+		}
+	} else {
+		d.Unavailable |= SectionSockets
+	}
+	if d.Memory, err = readMemoryMaps(ctx, p); err != nil {
+		d.Unavailable |= SectionMemory
+	}
+	if limits, err := p.RlimitUsageWithContext(ctx, true); err == nil {
+		for _, l := range limits {
+			if name, ok := rlimitNames[l.Resource]; ok {
+				d.Limits = append(d.Limits, ProcessLimit{Name: name, Soft: l.Soft, Hard: l.Hard, Used: l.Used})
+			}
+		}
+	} else {
+		d.Unavailable |= SectionLimits
+	}
+	if threads, err := p.ThreadsWithContext(ctx); err == nil {
+		now := c.now()
+		for tid, times := range threads {
+			rates, _, _ := c.threads.update(strconv.Itoa(int(tid)), now, microseconds(times.User+times.System))
+			name, _ := os.ReadFile(filepath.Join(c.procRoot, strconv.Itoa(int(p.Pid)), "task", strconv.Itoa(int(tid)), "comm"))
+			d.Threads = append(d.Threads, ThreadStat{Tid: tid, Name: strings.TrimSpace(string(name)), CPUPercent: rates[0] / 1e4})
+		}
+		c.threads.prune()
+		sort.Slice(d.Threads, func(i, j int) bool {
+			if d.Threads[i].CPUPercent != d.Threads[j].CPUPercent {
+				return d.Threads[i].CPUPercent > d.Threads[j].CPUPercent
+			}
+			return d.Threads[i].Tid < d.Threads[j].Tid
+		})
+	} else {
+		d.Unavailable |= SectionThreads
+	}
+	if d.Cgroup, err = readCgroup(c.procRoot, p.Pid); err != nil {
+		d.Unavailable |= SectionCgroup
+	}
+	return d
+}
+
+// socketType names a socket by address family and socket type.
+func socketType(family, typ uint32) string {
+	const afUnix, afInet6, sockDgram = 1, 10, 2
+	switch {
+	case family == afUnix:
+		return "unix"
+	case typ == sockDgram && family == afInet6:
+		return "udp6"
+	case typ == sockDgram:
+		return "udp"
+	case family == afInet6:
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// socketStatus returns the state of a connection-oriented socket, or "" for the
+// placeholder gopsutil reports for connectionless ones.
+func socketStatus(status string) string {
+	if status == "NONE" {
+		return ""
+	}
+	return status
+}
+
+// socketAddr formats a socket address; unix sockets are identified by their path.
+func socketAddr(family uint32, ip string, port uint32) string {
+	const afUnix = 1
+	if family == afUnix || (ip == "" && port == 0) {
+		return ip
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(int(port)))
+}
+
+// processGone reports whether the process with pid started at createTime has exited.
+// A different create time means the pid now belongs to another process. Process caches
+// its create time, so it is read through a fresh handle.
+func processGone(ctx context.Context, pid int32, createTime int64) bool {
+	if exists, err := process.PidExistsWithContext(ctx, pid); err != nil || !exists {
+		return true
+	}
+	proc, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return true
+	}
+	ct, err := proc.CreateTimeWithContext(ctx)
+	return err != nil || ct != createTime
+}
+
+// StartProcessDetailMonitor starts a goroutine that periodically sends the details of the
+// process with the given pid to the returned channel. The process is read from procRoot,
+// usually /proc.
+// The channel is closed when the provided context is cancelled or the process exits.
+func StartProcessDetailMonitor(ctx context.Context, interval time.Duration, pid int32, procRoot string) <-chan ProcessDetail {
+	ch := make(chan ProcessDetail)
+	ctx = withProcRoot(ctx, procRoot)
+	go func() {
+		defer close(ch)
+		proc, err := process.NewProcessWithContext(ctx, pid)
+		if err != nil {
+			// The process exited; the closed channel tells the consumer.
+			return
+		}
+		createTime, _ := proc.CreateTimeWithContext(ctx)
+		collector := &detailCollector{proc: proc, procRoot: procRoot, now: time.Now, threads: newRateTracker()}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if processGone(ctx, pid, createTime) {
+				return
+			}
+
+			select {
+			case ch <- collector.collect(ctx):
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,49 @@
+package hundler
+
+import (
+	"context"
+	"sort"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// readMemoryMaps summarizes the memory mappings of a process from /proc/<pid>/smaps.
+func readMemoryMaps(ctx context.Context, p *process.Process) (MemoryMapsSummary, error) {
+	maps, err := p.MemoryMapsWithContext(ctx, false)
+	if err != nil {
+		return MemoryMapsSummary{}, err
+	}
+	return summarizeMemoryMaps(*maps), nil
+}
+
+// summarizeMemoryMaps sums up mappings as read from smaps, whose sizes are in kB.
+func summarizeMemoryMaps(maps []process.MemoryMapsStat) MemoryMapsSummary {
+	s := MemoryMapsSummary{Mappings: len(maps)}
+	byPath := make(map[string]*MemoryMapping)
+	for _, m := range maps {
+		s.Size += m.Size * 1024
+		s.Rss += m.Rss * 1024
+		s.Pss += m.Pss * 1024
+		s.Swap += m.Swap * 1024
+		mm, ok := byPath[m.Path]
+		if !ok {
+			mm = &MemoryMapping{Path: m.Path}
+			byPath[m.Path] = mm
+		}
+		mm.Size += m.Size * 1024
+		mm.Rss += m.Rss * 1024
+	}
+	for _, mm := range byPath {
+		s.Top = append(s.Top, *mm)
+	}
+	sort.Slice(s.Top, func(i, j int) bool {
+		if s.Top[i].Rss != s.Top[j].Rss {
+			return s.Top[i].Rss > s.Top[j].Rss
+		}
+		return s.Top[i].Path < s.Top[j].Path
+	})
+	if len(s.Top) > topMappings {
+		s.Top = s.Top[:topMappings]
+	}
+	return s
+}
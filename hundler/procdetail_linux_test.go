@@ -0,0 +1,33 @@
+package hundler
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+func TestSummarizeMemoryMaps(t *testing.T) {
+	maps := []process.MemoryMapsStat{
+		{Path: "/usr/lib/libc.so.6", Size: 100, Rss: 40, Pss: 10},
+		{Path: "/usr/lib/libc.so.6", Size: 20, Rss: 8, Pss: 2},
+		{Path: "", Size: 1000, Rss: 900, Pss: 900, Swap: 50},
+		{Path: "[stack]", Size: 132, Rss: 12, Pss: 12},
+	}
+	s := summarizeMemoryMaps(maps)
+	if s.Mappings != 4 || s.Size != 1252*1024 || s.Rss != 960*1024 || s.Pss != 924*1024 || s.Swap != 50*1024 {
+		t.Errorf("summary = %+v", s)
+	}
+	want := []MemoryMapping{
+		{Path: "", Size: 1000 * 1024, Rss: 900 * 1024},
+		{Path: "/usr/lib/libc.so.6", Size: 120 * 1024, Rss: 48 * 1024},
+		{Path: "[stack]", Size: 132 * 1024, Rss: 12 * 1024},
+	}
+	if len(s.Top) != len(want) {
+		t.Fatalf("top mappings = %+v", s.Top)
+	}
+	for i, w := range want {
+		if s.Top[i] != w {
+			t.Errorf("top[%d] = %+v, want %+v", i, s.Top[i], w)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package hundler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// readMemoryMaps is only implemented on Linux, where smaps lists the mappings.
+func readMemoryMaps(context.Context, *process.Process) (MemoryMapsSummary, error) {
+	return MemoryMapsSummary{}, errors.ErrUnsupported
+}
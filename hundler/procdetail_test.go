@@ -0,0 +1,121 @@
+package hundler
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestSocketFormatting(t *testing.T) {
+	tests := []struct {
+		family, typ uint32
+		ip          string
+		port        uint32
+		wantType    string
+		wantAddr    string
+	}{
+		{2, 1, "127.0.0.1", 8080, "tcp", "127.0.0.1:8080"},
+		{10, 1, "::1", 443, "tcp6", "[::1]:443"},
+		{2, 2, "0.0.0.0", 53, "udp", "0.0.0.0:53"},
+		{10, 2, "::", 5353, "udp6", "[::]:5353"},
+		{1, 1, "/run/dbus/system_bus_socket", 0, "unix", "/run/dbus/system_bus_socket"},
+		{2, 1, "", 0, "tcp", ""},
+	}
+	for _, tt := range tests {
+		if got := socketType(tt.family, tt.typ); got != tt.wantType {
+			t.Errorf("socketType(%d, %d) = %q, want %q", tt.family, tt.typ, got, tt.wantType)
+		}
+		if got := socketAddr(tt.family, tt.ip, tt.port); got != tt.wantAddr {
+			t.Errorf("socketAddr(%d, %q, %d) = %q, want %q", tt.family, tt.ip, tt.port, got, tt.wantAddr)
+		}
+	}
+	if socketStatus("NONE") != "" || socketStatus("LISTEN") != "LISTEN" {
+		t.Error("socketStatus should only blank out NONE")
+	}
+}
+
+func TestStartProcessDetailMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 100 * time.Millisecond
+	detailCh := StartProcessDetailMonitor(ctx, interval, int32(os.Getpid()), "/proc")
+
+	// Test if at least one value is received
+	select {
+	case d := <-detailCh:
+		if d.Pid != int32(os.Getpid()) || d.Name == "" {
+			t.Errorf("unexpected detail for pid %d: %+v", os.Getpid(), d)
+		}
+		if d.Available(SectionThreads) && len(d.Threads) == 0 {
+			t.Error("expected at least one thread")
+		}
+		if wd, _ := os.Getwd(); d.Available(SectionCwd) && d.Cwd != wd {
+			t.Errorf("cwd = %q, want %q", d.Cwd, wd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for process detail")
+	}
+
+	// Test if monitoring stops after context cancellation
+	cancel()
+	select {
+	case _, ok := <-detailCh:
+		if ok {
+			t.Error("Detail channel should be closed after context cancellation")
+		}
+	case <-time.After(500 * time.Millisecond):
+		// This is acceptable
+	}
+}
+
+func TestStartProcessDetailMonitorMissingProcess(t *testing.T) {
+	// Pids are capped well below this on every supported platform.
+	detailCh := StartProcessDetailMonitor(context.Background(), time.Second, 1<<30, "/proc")
+	select {
+	case _, ok := <-detailCh:
+		if ok {
+			t.Error("Detail channel should be closed when the process doesn't exist")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for the detail channel to close")
+	}
+}
+
+func TestStartProcessDetailMonitorExitedProcess(t *testing.T) {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start a child process: %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	detailCh := StartProcessDetailMonitor(ctx, 50*time.Millisecond, int32(cmd.Process.Pid), "/proc")
+	select {
+	case d := <-detailCh:
+		if d.Pid != int32(cmd.Process.Pid) {
+			t.Fatalf("unexpected detail: %+v", d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for process detail")
+	}
+
+	// Reap the child so that its pid disappears.
+	cmd.Process.Kill()
+	cmd.Wait()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-detailCh:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Detail channel should be closed after the process exited")
+		}
+	}
+}
@@ -2,31 +2,307 @@ package hundler
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"runtime"
+	"strconv"
 	"time"
 
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/mem"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
 // ProcessStat holds periodic process information
 type ProcessStat struct {
-	Pid         int32
-	Name        string
-	CPUPercent  float64
-	MemoryBytes uint64
+	Pid           int32
+	PPid          int32
+	Name          string
+	Username      string
+	UID           uint32
+	State         string // Single letter state as shown by ps: R, S, D, Z, T, I...
+	NumThreads    int32
+	Nice          int32
+	Cmdline       string
+	Cgroup        string // Control group path, e.g. /user.slice/user-1000.slice/session-2.scope
+	CreateTime    time.Time
+	CPUPercent    float64 // CPU usage over the last refresh interval; 100 is one full core
+	MemoryBytes   uint64  // Resident Set Size
+	VMS           uint64  // Virtual Memory Size
+	MemoryPercent float64 // MemoryBytes as a percentage of total physical memory
+
+	ReadBytesPerSec  float64 // Bytes read from storage per second
+	WriteBytesPerSec float64 // Bytes written to storage per second
+	NumFDs           int32   // Open file descriptors
+
+	Unavailable ProcessField // Fields that couldn't be read, e.g. due to missing permissions
+}
+
+// ProcessField identifies a group of ProcessStat fields that may be unavailable.
+type ProcessField uint32
+
+const (
+	FieldCPU     ProcessField = 1 << iota // CPUPercent
+	FieldMemory                           // MemoryBytes, VMS and MemoryPercent
+	FieldIO                               // ReadBytesPerSec and WriteBytesPerSec
+	FieldFDs                              // NumFDs
+	FieldUser                             // Username and UID
+	FieldPPid                             // PPid
+	FieldCmdline                          // Cmdline
+	FieldState                            // State
+	FieldThreads                          // NumThreads
+	FieldNice                             // Nice
+	FieldCgroup                           // Cgroup
+)
+
+// Available reports whether the fields identified by f could be read.
+func (p ProcessStat) Available(f ProcessField) bool {
+	return p.Unavailable&f == 0
+}
+
+// processHandle is the part of *process.Process the process collector relies on for
+// the fields that aren't part of a processSample.
+type processHandle interface {
+	NameWithContext(ctx context.Context) (string, error)
+	UsernameWithContext(ctx context.Context) (string, error)
+	CmdlineWithContext(ctx context.Context) (string, error)
+	IOCountersWithContext(ctx context.Context) (*process.IOCountersStat, error)
+	NumFDsWithContext(ctx context.Context) (int32, error)
+}
+
+// processSource lists running processes, samples them and opens handles to them.
+type processSource interface {
+	Pids(ctx context.Context) ([]int32, error)
+	Sample(ctx context.Context, pid int32) (processSample, error)
+	Open(ctx context.Context, pid int32) (processHandle, error)
+	TotalMemory(ctx context.Context) (uint64, error)
+	Cgroup(ctx context.Context, pid int32) (string, error)
+}
+
+// systemProcesses is the processSource backed by gopsutil and, for samples and cgroups,
+// by procRoot. The contexts passed to it and to its handles must come from withProcRoot
+// so that gopsutil reads from procRoot as well.
+type systemProcesses struct {
+	procRoot string
+	bootTime uint64 // Seconds since the epoch; process start times count from here
+}
+
+func newSystemProcesses(ctx context.Context, procRoot string) systemProcesses {
+	bootTime, err := host.BootTimeWithContext(ctx)
+	if err != nil {
+		log.Printf("Error getting boot time: %v", err)
+	}
+	return systemProcesses{procRoot: procRoot, bootTime: bootTime}
+}
+
+func (systemProcesses) Pids(ctx context.Context) ([]int32, error) {
+	return process.PidsWithContext(ctx)
+}
+
+// Open skips the existence check process.NewProcess makes: the collector only opens
+// processes it just sampled.
+func (systemProcesses) Open(_ context.Context, pid int32) (processHandle, error) {
+	return &process.Process{Pid: pid}, nil
+}
+
+func (systemProcesses) TotalMemory(ctx context.Context) (uint64, error) {
+	v, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return v.Total, nil
+}
+
+func (s systemProcesses) Cgroup(_ context.Context, pid int32) (string, error) {
+	return readCgroup(s.procRoot, pid)
+}
+
+// processKey identifies a process across refreshes. The create time guards against
+// a pid being reused by a different process between two refreshes.
+type processKey struct {
+	pid        int32
+	createTime int64
+}
+
+func (k processKey) String() string {
+	return fmt.Sprintf("%d/%d", k.pid, k.createTime)
+}
+
+// trackedProcess is a cached handle together with the fields that never change during
+// the life of a process and the CPU time seen at the previous refresh.
+type trackedProcess struct {
+	handle  processHandle
+	static  ProcessStat // Pid, Name, Username, Cmdline, Cgroup and CreateTime
+	cpuTime float64     // user+system seconds
+	sampled time.Time
+}
+
+// openTracked reads the fields of a newly seen process that stay fixed for its lifetime.
+// Only the name is required; fields that can't be read are flagged as unavailable, and an
+// owner that can't be resolved to a user name is shown by uid.
+func openTracked(ctx context.Context, h processHandle, pid int32, sample processSample) (*trackedProcess, error) {
+	name, err := h.NameWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	static := ProcessStat{Pid: pid, Name: name, CreateTime: time.UnixMilli(sample.createTime)}
+	if sample.unavailable&FieldUser == 0 {
+		if user, err := h.UsernameWithContext(ctx); err == nil {
+			static.Username = user
+		} else {
+			static.Username = strconv.FormatUint(uint64(sample.uid), 10)
+		}
+	}
+	if static.Cmdline, err = h.CmdlineWithContext(ctx); err != nil {
+		static.Unavailable |= FieldCmdline
+	}
+	return &trackedProcess{handle: h, static: static}, nil
+}
+
+// processCollector keeps process handles across refreshes so CPU usage can be computed
+// from the CPU time consumed during each interval rather than since process start.
+type processCollector struct {
+	source   processSource
+	now      func() time.Time
+	tracked  map[processKey]*trackedProcess
+	io       *rateTracker
+	previous time.Time // When the previous collect started, zero before the first
+}
+
+func newProcessCollector(source processSource) *processCollector {
+	return &processCollector{
+		source:  source,
+		now:     time.Now,
+		tracked: make(map[processKey]*trackedProcess),
+		io:      newRateTracker(),
+	}
+}
+
+// collect samples every running process once. Processes that exited since the previous
+// call are evicted from the cache. Fields that can't be read, typically because another
+// user's process denies access, are flagged in ProcessStat.Unavailable instead of hiding
+// the whole process.
+func (c *processCollector) collect(ctx context.Context) ([]ProcessStat, error) {
+	started := c.now()
+	pids, err := c.source.Pids(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { c.previous = started }()
+	totalMemory, _ := c.source.TotalMemory(ctx)
+
+	seen := make(map[processKey]bool, len(pids))
+	var stats []ProcessStat
+	for _, pid := range pids {
+		sample, err := c.source.Sample(ctx, pid)
+		if err != nil {
+			// The process most likely exited already.
+			continue
+		}
+		key := processKey{pid: pid, createTime: sample.createTime}
+
+		// Handles are only opened for processes not seen before; the sample is
+		// enough to tell whether a cached one still belongs to the same process.
+		tp, ok := c.tracked[key]
+		if !ok {
+			h, err := c.source.Open(ctx, pid)
+			if err != nil {
+				continue
+			}
+			if tp, err = openTracked(ctx, h, pid, sample); err != nil {
+				continue
+			}
+			// Processes rarely change cgroups, so the one seen first is kept.
+			if tp.static.Cgroup, err = c.source.Cgroup(ctx, pid); err != nil {
+				tp.static.Unavailable |= FieldCgroup
+			}
+		}
+		c.tracked[key] = tp
+		seen[key] = true
+
+		s := tp.static
+		s.Unavailable |= sample.unavailable
+		s.PPid, s.UID, s.State = sample.ppid, sample.uid, sample.state
+		s.NumThreads, s.Nice = sample.numThreads, sample.nice
+		now := c.now()
+
+		if sample.unavailable&FieldCPU == 0 {
+			switch {
+			case !tp.sampled.IsZero():
+				if elapsed := now.Sub(tp.sampled).Seconds(); elapsed > 0 && sample.cpuTime >= tp.cpuTime {
+					s.CPUPercent = (sample.cpuTime - tp.cpuTime) / elapsed * 100
+				}
+			case !c.previous.IsZero() && !s.CreateTime.Before(c.previous):
+				// Started since the previous refresh, so the average since start
+				// covers no more than the interval.
+				if lifetime := now.Sub(s.CreateTime).Seconds(); lifetime > 0 {
+					s.CPUPercent = sample.cpuTime / lifetime * 100
+				}
+			default:
+				// Running before the collector first saw it: the average since start
+				// says little about current usage, so wait for a second sample.
+				s.Unavailable |= FieldCPU
+			}
+			tp.cpuTime, tp.sampled = sample.cpuTime, now
+		}
+
+		if sample.unavailable&FieldMemory == 0 {
+			s.MemoryBytes = sample.rss
+			s.VMS = sample.vms
+			if totalMemory > 0 {
+				s.MemoryPercent = float64(sample.rss) / float64(totalMemory) * 100
+			}
+		}
+
+		if io, err := tp.handle.IOCountersWithContext(ctx); err == nil {
+			read, write := storageBytes(io)
+			r, _, _ := c.io.update(key.String(), now, read, write)
+			s.ReadBytesPerSec, s.WriteBytesPerSec = r[0], r[1]
+		} else {
+			s.Unavailable |= FieldIO
+		}
+
+		if fds, err := tp.handle.NumFDsWithContext(ctx); err == nil {
+			s.NumFDs = fds
+		} else {
+			s.Unavailable |= FieldFDs
+		}
+
+		stats = append(stats, s)
+	}
+
+	for key := range c.tracked {
+		if !seen[key] {
+			delete(c.tracked, key)
+		}
+	}
+	c.io.prune()
+	return stats, nil
+}
+
+// storageBytes returns the bytes a process read from and wrote to storage. Linux reports
+// storage I/O separately from all read/write syscalls; elsewhere only the latter exists.
+func storageBytes(io *process.IOCountersStat) (read, write uint64) {
+	if runtime.GOOS == "linux" {
+		return io.DiskReadBytes, io.DiskWriteBytes
+	}
+	return io.ReadBytes, io.WriteBytes
 }
 
 // StartProcessMonitor starts a goroutine that periodically sends a list of process stats to the returned channel.
+// On Linux, processes are read from procRoot, usually /proc.
 // The channel is closed when the provided context is cancelled.
-func StartProcessMonitor(ctx context.Context, interval time.Duration) <-chan []ProcessStat {
+func StartProcessMonitor(ctx context.Context, interval time.Duration, procRoot string) <-chan []ProcessStat {
 	ch := make(chan []ProcessStat)
+	ctx = withProcRoot(ctx, procRoot)
+	collector := newProcessCollector(newSystemProcesses(ctx, procRoot))
 	go func() {
 		defer close(ch)
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
-			procs, err := process.Processes()
+			stats, err := collector.collect(ctx)
 			if err != nil {
 				log.Printf("Error getting processes: %v", err)
 				select {
@@ -37,29 +313,6 @@ func StartProcessMonitor(ctx context.Context, interval time.Duration) <-chan []P
 				}
 			}
 
-			var stats []ProcessStat
-			for _, p := range procs {
-				name, err := p.NameWithContext(ctx)
-				if err != nil {
-					continue
-				}
-				cpuPercent, err := p.CPUPercentWithContext(ctx)
-				if err != nil {
-					continue
-				}
-				memInfo, err := p.MemoryInfoWithContext(ctx)
-				if err != nil {
-					continue
-				}
-
-				stats = append(stats, ProcessStat{
-					Pid:         p.Pid,
-					Name:        name,
-					CPUPercent:  cpuPercent,
-					MemoryBytes: memInfo.RSS, // Resident Set Size
-				})
-			}
-
 			select {
 			case ch <- stats:
 			case <-ctx.Done():
@@ -75,4 +328,4 @@ func StartProcessMonitor(ctx context.Context, interval time.Duration) <-chan []P
 		}
 	}()
 	return ch
-}
\ No newline at end of file
+}
@@ -0,0 +1,7 @@
+package hundler
+
+import "context"
+
+func (s systemProcesses) Sample(_ context.Context, pid int32) (processSample, error) {
+	return readProcessSample(s.procRoot, pid, s.bootTime)
+}
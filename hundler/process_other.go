@@ -0,0 +1,76 @@
+//go:build !linux
+
+package hundler
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// stateLetters maps gopsutil status names back to the letters used by ps and top.
+var stateLetters = map[string]string{
+	process.Running: "R",
+	process.Sleep:   "S",
+	process.Blocked: "D",
+	process.Zombie:  "Z",
+	process.Stop:    "T",
+	process.Idle:    "I",
+	process.Wait:    "W",
+	process.Lock:    "L",
+}
+
+// stateLetter returns the ps-style letter for a gopsutil status, or "?" if unknown.
+func stateLetter(status []string) string {
+	if len(status) == 0 {
+		return "?"
+	}
+	if l, ok := stateLetters[status[0]]; ok {
+		return l
+	}
+	return "?"
+}
+
+// Sample asks gopsutil for each field in turn, since there's no /proc/<pid>/stat to
+// parse outside of Linux. Only the create time is required.
+func (systemProcesses) Sample(ctx context.Context, pid int32) (processSample, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return processSample{}, err
+	}
+	var s processSample
+	if s.createTime, err = p.CreateTimeWithContext(ctx); err != nil {
+		return processSample{}, err
+	}
+	if s.ppid, err = p.PpidWithContext(ctx); err != nil {
+		s.unavailable |= FieldPPid
+	}
+	if uids, err := p.UidsWithContext(ctx); err == nil && len(uids) > 0 {
+		s.uid = uids[0]
+	} else {
+		s.unavailable |= FieldUser
+	}
+	if status, err := p.StatusWithContext(ctx); err == nil {
+		s.state = stateLetter(status)
+	} else {
+		s.state = "?"
+		s.unavailable |= FieldState
+	}
+	if s.numThreads, err = p.NumThreadsWithContext(ctx); err != nil {
+		s.unavailable |= FieldThreads
+	}
+	if s.nice, err = p.NiceWithContext(ctx); err != nil {
+		s.unavailable |= FieldNice
+	}
+	if times, err := p.TimesWithContext(ctx); err == nil {
+		s.cpuTime = times.User + times.System
+	} else {
+		s.unavailable |= FieldCPU
+	}
+	if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+		s.rss, s.vms = mem.RSS, mem.VMS
+	} else {
+		s.unavailable |= FieldMemory
+	}
+	return s, nil
+}
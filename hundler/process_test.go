@@ -0,0 +1,382 @@
+package hundler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// fakeProcess is a processHandle whose counters are set directly by the test.
+type fakeProcess struct {
+	createTime int64
+	name       string
+	user       string
+	ppid       int32
+	status     string
+	cpuSeconds float64
+	rss        uint64
+	diskRead   uint64
+	diskWrite  uint64
+	denied     bool // Simulates another user's process: /proc/<pid>/io and fd are off limits
+}
+
+var errPermission = errors.New("permission denied")
+
+func (p *fakeProcess) NameWithContext(context.Context) (string, error) {
+	return p.name, nil
+}
+
+func (p *fakeProcess) UsernameWithContext(context.Context) (string, error) {
+	if p.user == "" {
+		return "", errors.New("unknown user")
+	}
+	return p.user, nil
+}
+
+func (p *fakeProcess) CmdlineWithContext(context.Context) (string, error) {
+	return "/usr/bin/" + p.name + " --flag", nil
+}
+
+func (p *fakeProcess) IOCountersWithContext(context.Context) (*process.IOCountersStat, error) {
+	if p.denied {
+		return nil, errPermission
+	}
+	return &process.IOCountersStat{DiskReadBytes: p.diskRead, DiskWriteBytes: p.diskWrite, ReadBytes: p.diskRead, WriteBytes: p.diskWrite}, nil
+}
+
+func (p *fakeProcess) NumFDsWithContext(context.Context) (int32, error) {
+	if p.denied {
+		return 0, errPermission
+	}
+	return 12, nil
+}
+
+func (p *fakeProcess) sample() processSample {
+	return processSample{
+		createTime: p.createTime,
+		ppid:       p.ppid,
+		uid:        1000,
+		state:      p.status,
+		numThreads: 4,
+		nice:       10,
+		cpuTime:    p.cpuSeconds,
+		rss:        p.rss,
+		vms:        4 * p.rss,
+	}
+}
+
+// fakeProcessSource serves fakeProcesses by pid.
+type fakeProcessSource struct {
+	procs map[int32]*fakeProcess
+	opens map[int32]int // Open calls per pid
+}
+
+func (s *fakeProcessSource) Pids(context.Context) ([]int32, error) {
+	var pids []int32
+	for pid := range s.procs {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids, nil
+}
+
+func (s *fakeProcessSource) Sample(_ context.Context, pid int32) (processSample, error) {
+	p, ok := s.procs[pid]
+	if !ok {
+		return processSample{}, errors.New("no such process")
+	}
+	return p.sample(), nil
+}
+
+func (s *fakeProcessSource) Open(_ context.Context, pid int32) (processHandle, error) {
+	if s.opens == nil {
+		s.opens = make(map[int32]int)
+	}
+	s.opens[pid]++
+	p, ok := s.procs[pid]
+	if !ok {
+		return nil, errors.New("no such process")
+	}
+	return p, nil
+}
+
+func (s *fakeProcessSource) TotalMemory(context.Context) (uint64, error) {
+	return 8 << 20, nil
+}
+
+func (s *fakeProcessSource) Cgroup(_ context.Context, pid int32) (string, error) {
+	if s.procs[pid].denied {
+		return "", errors.New("permission denied")
+	}
+	return fmt.Sprintf("/user.slice/%d.scope", pid), nil
+}
+
+// fakeClock is a manually advanced clock for the process collector.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func collectByPid(t *testing.T, c *processCollector) map[int32]ProcessStat {
+	t.Helper()
+	stats, err := c.collect(context.Background())
+	if err != nil {
+		t.Fatalf("collect() error: %v", err)
+	}
+	byPid := make(map[int32]ProcessStat)
+	for _, s := range stats {
+		byPid[s.Pid] = s
+	}
+	return byPid
+}
+
+func TestProcessCollectorIntervalCPU(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	clock := &fakeClock{t: start}
+	source := &fakeProcessSource{procs: map[int32]*fakeProcess{
+		// Has been idle for an hour after burning 360 CPU seconds, then starts spinning.
+		100: {createTime: start.Add(-time.Hour).UnixMilli(), name: "build", cpuSeconds: 360, rss: 1 << 20},
+	}}
+	c := newProcessCollector(source)
+	c.now = clock.now
+
+	// The lifetime average (10%) would be misleading, so the first sample has no CPU usage.
+	stats := collectByPid(t, c)
+	if got := stats[100]; got.Available(FieldCPU) || got.CPUPercent != 0 {
+		t.Errorf("first sample CPUPercent = %f, available %t, want it unavailable", got.CPUPercent, got.Available(FieldCPU))
+	}
+	if !stats[100].Available(FieldMemory) {
+		t.Error("the first sample should still have memory usage")
+	}
+
+	// Spinning a full core for two seconds must show as 100%, not the lifetime average.
+	clock.t = clock.t.Add(2 * time.Second)
+	source.procs[100].cpuSeconds += 2
+	stats = collectByPid(t, c)
+	if got := stats[100].CPUPercent; got != 100 {
+		t.Errorf("interval CPUPercent = %f, want 100", got)
+	}
+	if got := stats[100].MemoryBytes; got != 1<<20 {
+		t.Errorf("MemoryBytes = %d, want %d", got, 1<<20)
+	}
+
+	// Going idle again drops straight to 0.
+	clock.t = clock.t.Add(2 * time.Second)
+	stats = collectByPid(t, c)
+	if got := stats[100].CPUPercent; got != 0 {
+		t.Errorf("idle CPUPercent = %f, want 0", got)
+	}
+
+	// A process started since the previous refresh has only been running for part of
+	// the interval, so its average since start is its usage.
+	source.procs[200] = &fakeProcess{createTime: clock.t.Add(time.Second).UnixMilli(), name: "make", cpuSeconds: 0.5}
+	clock.t = clock.t.Add(2 * time.Second)
+	stats = collectByPid(t, c)
+	if got := stats[200]; !got.Available(FieldCPU) || got.CPUPercent != 50 {
+		t.Errorf("new process CPUPercent = %f, available %t, want 50", got.CPUPercent, got.Available(FieldCPU))
+	}
+}
+
+func TestProcessCollectorEvictsAndDetectsPidReuse(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	clock := &fakeClock{t: start}
+	source := &fakeProcessSource{procs: map[int32]*fakeProcess{
+		100: {createTime: start.Add(-time.Hour).UnixMilli(), name: "old", cpuSeconds: 3600},
+		200: {createTime: start.Add(-time.Hour).UnixMilli(), name: "short-lived", cpuSeconds: 1},
+	}}
+	c := newProcessCollector(source)
+	c.now = clock.now
+	collectByPid(t, c)
+
+	// pid 200 exits; pid 100 exits and is reused by a new process started one second ago.
+	clock.t = clock.t.Add(time.Second)
+	delete(source.procs, 200)
+	source.procs[100] = &fakeProcess{createTime: clock.t.Add(-time.Second).UnixMilli(), name: "new", cpuSeconds: 0.5}
+
+	stats := collectByPid(t, c)
+	if _, ok := stats[200]; ok {
+		t.Error("exited pid 200 should not be reported")
+	}
+	if got := stats[100].Name; got != "new" {
+		t.Errorf("reused pid 100 Name = %q, want %q", got, "new")
+	}
+	if got := stats[100].CPUPercent; got != 50 {
+		t.Errorf("reused pid 100 CPUPercent = %f, want 50", got)
+	}
+	if len(c.tracked) != 1 {
+		t.Errorf("collector should track only live processes, tracking %d", len(c.tracked))
+	}
+}
+
+func TestProcessCollectorOpensNewProcessesOnly(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	clock := &fakeClock{t: start}
+	source := &fakeProcessSource{procs: map[int32]*fakeProcess{
+		100: {createTime: start.Add(-time.Hour).UnixMilli(), name: "init"},
+		200: {createTime: start.Add(-time.Hour).UnixMilli(), name: "sshd"},
+	}}
+	c := newProcessCollector(source)
+	c.now = clock.now
+	for range 3 {
+		clock.t = clock.t.Add(time.Second)
+		collectByPid(t, c)
+	}
+	if source.opens[100] != 1 || source.opens[200] != 1 {
+		t.Errorf("live processes opened %v times, want once each", source.opens)
+	}
+
+	// A new process and a reused pid are opened once more each.
+	source.procs[300] = &fakeProcess{createTime: clock.t.UnixMilli(), name: "cron"}
+	source.procs[200] = &fakeProcess{createTime: clock.t.UnixMilli(), name: "bash"}
+	for range 2 {
+		clock.t = clock.t.Add(time.Second)
+		collectByPid(t, c)
+	}
+	if source.opens[100] != 1 || source.opens[200] != 2 || source.opens[300] != 1 {
+		t.Errorf("Open calls per pid = %v, want 100:1 200:2 300:1", source.opens)
+	}
+}
+
+func TestStartProcessMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 100 * time.Millisecond
+	procCh := StartProcessMonitor(ctx, interval, "/proc")
+
+	// Test if at least one value is received
+	select {
+	case stats := <-procCh:
+		if len(stats) == 0 {
+			t.Error("expected at least one process")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for Process stats")
+	}
+
+	// Test if monitoring stops after context cancellation
+	cancel()
+	select {
+	case _, ok := <-procCh:
+		if ok {
+			t.Error("Process channel should be closed after context cancellation")
+		}
+	case <-time.After(500 * time.Millisecond):
+		// This is acceptable
+	}
+}
+
+func TestStartProcessMonitorProcRoot(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("procRoot is only read on Linux")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Everything must come from the fake root, none of it from this system's /proc.
+	var stats []ProcessStat
+	select {
+	case stats = <-StartProcessMonitor(ctx, time.Second, "testdata/proc"):
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for Process stats")
+	}
+	if len(stats) != 2 {
+		t.Fatalf("got %d processes, want the 2 in testdata/proc: %+v", len(stats), stats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Pid < stats[j].Pid })
+
+	kthreadd, tmux := stats[0], stats[1]
+	if kthreadd.Pid != 2 || kthreadd.Name != "kthreadd" || kthreadd.Username != "root" || kthreadd.MemoryBytes != 0 {
+		t.Errorf("unexpected kernel thread: %+v", kthreadd)
+	}
+	if tmux.Pid != 4242 || tmux.Name != "tmux: server (1)" || tmux.Cmdline != "tmux: server (1) -L default" {
+		t.Errorf("unexpected name or cmdline: %+v", tmux)
+	}
+	if tmux.PPid != 1 || tmux.UID != 1000 || tmux.State != "S" || tmux.NumThreads != 3 || tmux.Nice != 5 {
+		t.Errorf("unexpected stat or status fields: %+v", tmux)
+	}
+	if tmux.MemoryBytes != 8000*1024 || tmux.MemoryPercent != 0.05 {
+		t.Errorf("memory = %d bytes, %f%%, want %d and 0.05%%", tmux.MemoryBytes, tmux.MemoryPercent, 8000*1024)
+	}
+	if tmux.Cgroup != "/user.slice/user-1000.slice/tmux.scope" {
+		t.Errorf("cgroup = %q", tmux.Cgroup)
+	}
+	if tmux.Available(FieldIO) || tmux.Available(FieldFDs) {
+		t.Errorf("I/O and FDs have no files in testdata/proc and should be unavailable: %b", tmux.Unavailable)
+	}
+}
+
+func TestProcessCollectorFields(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	created := start.Add(-time.Minute)
+	source := &fakeProcessSource{procs: map[int32]*fakeProcess{
+		100: {createTime: created.UnixMilli(), name: "postgres", user: "postgres", ppid: 1, status: "R", rss: 1 << 20},
+		101: {createTime: created.UnixMilli(), name: "defunct", ppid: 100, status: "Z"},
+	}}
+	c := newProcessCollector(source)
+	c.now = func() time.Time { return start }
+
+	stats := collectByPid(t, c)
+	pg := stats[100]
+	if pg.Username != "postgres" || pg.UID != 1000 || pg.PPid != 1 {
+		t.Errorf("unexpected owner/parent fields: %+v", pg)
+	}
+	if pg.State != "R" || pg.NumThreads != 4 || pg.Nice != 10 {
+		t.Errorf("unexpected state fields: %+v", pg)
+	}
+	if pg.Cmdline != "/usr/bin/postgres --flag" || !pg.CreateTime.Equal(created) {
+		t.Errorf("unexpected cmdline/create time: %+v", pg)
+	}
+	if pg.Cgroup != "/user.slice/100.scope" || !pg.Available(FieldCgroup) {
+		t.Errorf("unexpected cgroup: %q", pg.Cgroup)
+	}
+	if pg.VMS != 4<<20 || pg.MemoryPercent != 12.5 {
+		t.Errorf("unexpected memory fields: VMS %d, MemoryPercent %f", pg.VMS, pg.MemoryPercent)
+	}
+
+	// An owner that can't be resolved to a name falls back to the numeric uid.
+	if z := stats[101]; z.State != "Z" || z.Username != "1000" {
+		t.Errorf("unexpected zombie fields: %+v", z)
+	}
+}
+
+func TestProcessCollectorIOAndPermissionDenied(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	clock := &fakeClock{t: start}
+	source := &fakeProcessSource{procs: map[int32]*fakeProcess{
+		100: {createTime: start.Add(-time.Hour).UnixMilli(), name: "dd", user: "me", status: "R", diskRead: 1000, diskWrite: 5000},
+		200: {createTime: start.Add(-time.Hour).UnixMilli(), name: "sshd", user: "root", status: "S", denied: true},
+	}}
+	c := newProcessCollector(source)
+	c.now = clock.now
+	collectByPid(t, c)
+
+	clock.t = clock.t.Add(2 * time.Second)
+	source.procs[100].diskRead += 4000
+	source.procs[100].diskWrite += 2_000_000
+
+	stats := collectByPid(t, c)
+	dd := stats[100]
+	if dd.ReadBytesPerSec != 2000 || dd.WriteBytesPerSec != 1_000_000 {
+		t.Errorf("dd I/O rates = %f read, %f write, want 2000 and 1000000", dd.ReadBytesPerSec, dd.WriteBytesPerSec)
+	}
+	if dd.NumFDs != 12 || !dd.Available(FieldIO|FieldFDs) {
+		t.Errorf("dd should have I/O and FD counts available: %+v", dd)
+	}
+
+	sshd, ok := stats[200]
+	if !ok {
+		t.Fatal("process with unreadable fields should still be reported")
+	}
+	if sshd.Available(FieldIO) || sshd.Available(FieldFDs) || sshd.Available(FieldCgroup) {
+		t.Errorf("sshd I/O, FD and cgroup fields should be flagged unavailable: %b", sshd.Unavailable)
+	}
+	if !sshd.Available(FieldCPU) || !sshd.Available(FieldMemory) || sshd.Name != "sshd" {
+		t.Errorf("sshd readable fields should stay available: %+v", sshd)
+	}
+}
@@ -0,0 +1,174 @@
+package hundler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ProcessFilter selects processes using a small query language. The expression is a
+// whitespace-separated list of terms that must all match:
+//
+//	postgres        case-insensitive substring of the name, command line or user
+//	/^pg_.*er$/     regular expression over the name, command line or user
+//	user:postgres   exact field match; also name:, state:, pid: and ppid:
+//	cpu>20          numeric comparison with >, >=, <, <= or =; also mem, mem%, read,
+//	mem>1GB         write, fds, threads and nice. Sizes accept SI (kB, MB, GB) and
+//	                binary (KiB, MiB, GiB) suffixes.
+type ProcessFilter struct {
+	expr  string
+	terms []func(p ProcessStat) bool
+}
+
+// String returns the expression the filter was parsed from.
+func (f ProcessFilter) String() string {
+	return f.expr
+}
+
+// Empty reports whether the filter matches every process.
+func (f ProcessFilter) Empty() bool {
+	return len(f.terms) == 0
+}
+
+// Match reports whether p satisfies every term of the filter.
+func (f ProcessFilter) Match(p ProcessStat) bool {
+	for _, term := range f.terms {
+		if !term(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the processes that match the filter, in their original order.
+func (f ProcessFilter) Apply(procs []ProcessStat) []ProcessStat {
+	if f.Empty() {
+		return procs
+	}
+	var matched []ProcessStat
+	for _, p := range procs {
+		if f.Match(p) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// processFilterFields are the string fields usable as "field:value" terms.
+var processFilterFields = map[string]func(p ProcessStat) string{
+	"user":  func(p ProcessStat) string { return p.Username },
+	"name":  func(p ProcessStat) string { return p.Name },
+	"state": func(p ProcessStat) string { return p.State },
+	"pid":   func(p ProcessStat) string { return strconv.Itoa(int(p.Pid)) },
+	"ppid":  func(p ProcessStat) string { return strconv.Itoa(int(p.PPid)) },
+}
+
+// processFilterNumbers are the numeric fields usable in comparisons.
+var processFilterNumbers = map[string]func(p ProcessStat) float64{
+	"cpu":     func(p ProcessStat) float64 { return p.CPUPercent },
+	"mem":     func(p ProcessStat) float64 { return float64(p.MemoryBytes) },
+	"mem%":    func(p ProcessStat) float64 { return p.MemoryPercent },
+	"read":    func(p ProcessStat) float64 { return p.ReadBytesPerSec },
+	"write":   func(p ProcessStat) float64 { return p.WriteBytesPerSec },
+	"fds":     func(p ProcessStat) float64 { return float64(p.NumFDs) },
+	"threads": func(p ProcessStat) float64 { return float64(p.NumThreads) },
+	"nice":    func(p ProcessStat) float64 { return float64(p.Nice) },
+}
+
+var comparisonTerm = regexp.MustCompile(`^([a-z%]+)(>=|<=|>|<|=)(.+)$`)
+
+// ParseProcessFilter parses a filter expression. An empty expression matches everything.
+func ParseProcessFilter(expr string) (ProcessFilter, error) {
+	f := ProcessFilter{expr: strings.TrimSpace(expr)}
+	for _, word := range strings.Fields(expr) {
+		term, err := parseFilterTerm(word)
+		if err != nil {
+			return ProcessFilter{}, err
+		}
+		f.terms = append(f.terms, term)
+	}
+	return f, nil
+}
+
+func parseFilterTerm(word string) (func(p ProcessStat) bool, error) {
+	if len(word) >= 2 && strings.HasPrefix(word, "/") && strings.HasSuffix(word, "/") {
+		re, err := regexp.Compile(word[1 : len(word)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", word, err)
+		}
+		return func(p ProcessStat) bool {
+			return re.MatchString(p.Name) || re.MatchString(p.Cmdline) || re.MatchString(p.Username)
+		}, nil
+	}
+
+	if m := comparisonTerm.FindStringSubmatch(word); m != nil {
+		if value, ok := processFilterNumbers[m[1]]; ok {
+			limit, err := parseFilterNumber(m[3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in %q: %w", word, err)
+			}
+			op := m[2]
+			return func(p ProcessStat) bool {
+				return compare(value(p), op, limit)
+			}, nil
+		}
+	}
+
+	if key, want, ok := strings.Cut(word, ":"); ok {
+		value, known := processFilterFields[key]
+		if !known {
+			return nil, fmt.Errorf("unknown filter field %q", key)
+		}
+		return func(p ProcessStat) bool {
+			return strings.EqualFold(value(p), want)
+		}, nil
+	}
+
+	needle := strings.ToLower(word)
+	return func(p ProcessStat) bool {
+		return strings.Contains(strings.ToLower(p.Name), needle) ||
+			strings.Contains(strings.ToLower(p.Cmdline), needle) ||
+			strings.Contains(strings.ToLower(p.Username), needle)
+	}, nil
+}
+
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	default:
+		return a == b
+	}
+}
+
+// byteSuffixes maps size suffixes (lower case) to their multiplier.
+var byteSuffixes = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "m": 1e6, "mb": 1e6, "g": 1e9, "gb": 1e9, "t": 1e12, "tb": 1e12,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+// parseFilterNumber parses a plain number or a size such as "1GB", "512MiB" or "1.5k".
+func parseFilterNumber(s string) (float64, error) {
+	i := strings.IndexFunc(s, func(r rune) bool { return unicode.IsLetter(r) })
+	if i < 0 {
+		i = len(s)
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, err
+	}
+	mult, ok := byteSuffixes[strings.ToLower(s[i:])]
+	if !ok {
+		return 0, fmt.Errorf("unknown size suffix %q", s[i:])
+	}
+	return n * mult, nil
+}
@@ -0,0 +1,59 @@
+package hundler
+
+import "testing"
+
+func TestProcessFilter(t *testing.T) {
+	procs := []ProcessStat{
+		{Pid: 1, Name: "systemd", Username: "root", State: "S", MemoryBytes: 10_000_000, Cmdline: "/sbin/init"},
+		{Pid: 200, Name: "postgres", Username: "postgres", State: "S", CPUPercent: 35, MemoryBytes: 2_000_000_000, Cmdline: "postgres -D /var/lib/pg"},
+		{Pid: 201, Name: "postgres", Username: "postgres", State: "Z", CPUPercent: 0, MemoryBytes: 0},
+		{Pid: 300, Name: "node", Username: "ci", State: "R", CPUPercent: 99, MemoryBytes: 600 << 20, Cmdline: "node build.js"},
+	}
+
+	tests := []struct {
+		expr string
+		want []int32
+	}{
+		{"", []int32{1, 200, 201, 300}},
+		{"post", []int32{200, 201}},
+		{"BUILD", []int32{300}},
+		{"user:postgres", []int32{200, 201}},
+		{"state:Z", []int32{201}},
+		{"cpu>20", []int32{200, 300}},
+		{"cpu>20 user:ci", []int32{300}},
+		{"mem>1GB", []int32{200}},
+		{"mem>=600MiB mem<1GB", []int32{300}},
+		{"mem<1k", []int32{201}},
+		{"/^(node|systemd)$/", []int32{1, 300}},
+		{"pid:1", []int32{1}},
+	}
+	for _, tt := range tests {
+		f, err := ParseProcessFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseProcessFilter(%q) error: %v", tt.expr, err)
+			continue
+		}
+		var got []int32
+		for _, p := range f.Apply(procs) {
+			got = append(got, p.Pid)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("filter %q matched %v, want %v", tt.expr, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("filter %q matched %v, want %v", tt.expr, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseProcessFilterErrors(t *testing.T) {
+	for _, expr := range []string{"/[unclosed/", "color:red", "mem>1XB", "cpu>abc"} {
+		if _, err := ParseProcessFilter(expr); err == nil {
+			t.Errorf("ParseProcessFilter(%q) should fail", expr)
+		}
+	}
+}
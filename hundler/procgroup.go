@@ -0,0 +1,78 @@
+package hundler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GroupBy names the ProcessStat field processes are grouped by.
+type GroupBy string
+
+const (
+	GroupByName   GroupBy = "name"
+	GroupByUser   GroupBy = "user"
+	GroupByCgroup GroupBy = "cgroup"
+)
+
+// ProcessGroup holds the summed usage of the processes sharing a group key.
+type ProcessGroup struct {
+	Key              string
+	Count            int
+	CPUPercent       float64
+	MemoryBytes      uint64 // Summed Resident Set Size
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	Pids             []int32
+}
+
+// ParseGroupBy validates the name of a grouping.
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch by := GroupBy(s); by {
+	case GroupByName, GroupByUser, GroupByCgroup:
+		return by, nil
+	}
+	return "", fmt.Errorf("unknown process grouping %q (want name, user or cgroup)", s)
+}
+
+// GroupKey returns the group p belongs to. Processes whose user or cgroup can't be
+// read are grouped under "?".
+func GroupKey(p ProcessStat, by GroupBy) string {
+	switch by {
+	case GroupByUser:
+		if !p.Available(FieldUser) {
+			return "?"
+		}
+		return p.Username
+	case GroupByCgroup:
+		if !p.Available(FieldCgroup) || p.Cgroup == "" {
+			return "?"
+		}
+		return p.Cgroup
+	default:
+		return p.Name
+	}
+}
+
+// GroupProcesses aggregates procs by the given field. Groups are ordered by key.
+func GroupProcesses(procs []ProcessStat, by GroupBy) []ProcessGroup {
+	index := make(map[string]int)
+	var groups []ProcessGroup
+	for _, p := range procs {
+		key := GroupKey(p, by)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ProcessGroup{Key: key})
+		}
+		g := &groups[i]
+		g.Count++
+		g.CPUPercent += p.CPUPercent
+		g.MemoryBytes += p.MemoryBytes
+		g.ReadBytesPerSec += p.ReadBytesPerSec
+		g.WriteBytesPerSec += p.WriteBytesPerSec
+		g.Pids = append(g.Pids, p.Pid)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
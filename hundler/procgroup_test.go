@@ -0,0 +1,51 @@
+package hundler
+
+import "testing"
+
+func TestGroupProcesses(t *testing.T) {
+	procs := []ProcessStat{
+		{Pid: 1, Name: "chrome", Username: "alice", Cgroup: "/user.slice", CPUPercent: 10, MemoryBytes: 100, ReadBytesPerSec: 5},
+		{Pid: 2, Name: "chrome", Username: "alice", Cgroup: "/user.slice", CPUPercent: 20, MemoryBytes: 200, WriteBytesPerSec: 7},
+		{Pid: 3, Name: "make", Username: "ci", Cgroup: "/system.slice/ci.service", CPUPercent: 90, MemoryBytes: 50},
+		{Pid: 4, Name: "sshd", Unavailable: FieldUser | FieldCgroup, MemoryBytes: 5},
+	}
+
+	byName := GroupProcesses(procs, GroupByName)
+	if len(byName) != 3 || byName[0].Key != "chrome" {
+		t.Fatalf("groups by name = %+v", byName)
+	}
+	chrome := byName[0]
+	if chrome.Count != 2 || chrome.CPUPercent != 30 || chrome.MemoryBytes != 300 || chrome.ReadBytesPerSec != 5 || chrome.WriteBytesPerSec != 7 {
+		t.Errorf("chrome totals = %+v", chrome)
+	}
+	if len(chrome.Pids) != 2 || chrome.Pids[0] != 1 || chrome.Pids[1] != 2 {
+		t.Errorf("chrome pids = %v, want [1 2]", chrome.Pids)
+	}
+
+	byUser := GroupProcesses(procs, GroupByUser)
+	keys := []string{"?", "alice", "ci"}
+	if len(byUser) != len(keys) {
+		t.Fatalf("groups by user = %+v", byUser)
+	}
+	for i, k := range keys {
+		if byUser[i].Key != k {
+			t.Errorf("user group %d = %q, want %q", i, byUser[i].Key, k)
+		}
+	}
+
+	byCgroup := GroupProcesses(procs, GroupByCgroup)
+	if len(byCgroup) != 3 || byCgroup[1].Key != "/user.slice" || byCgroup[1].Count != 2 {
+		t.Errorf("groups by cgroup = %+v", byCgroup)
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for _, s := range []string{"name", "user", "cgroup"} {
+		if by, err := ParseGroupBy(s); err != nil || string(by) != s {
+			t.Errorf("ParseGroupBy(%q) = %q, %v", s, by, err)
+		}
+	}
+	if _, err := ParseGroupBy("exe"); err == nil {
+		t.Error("ParseGroupBy(\"exe\") should fail")
+	}
+}
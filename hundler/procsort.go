@@ -0,0 +1,27 @@
+package hundler
+
+import "fmt"
+
+// ProcessSortKeys are the keys accepted by ProcessLess.
+var ProcessSortKeys = []string{"pid", "name", "cpu", "mem", "read", "write", "fds"}
+
+// ProcessLess returns the ascending order of processes by the named key.
+func ProcessLess(key string) (func(a, b ProcessStat) bool, error) {
+	switch key {
+	case "pid":
+		return func(a, b ProcessStat) bool { return a.Pid < b.Pid }, nil
+	case "name":
+		return func(a, b ProcessStat) bool { return a.Name < b.Name }, nil
+	case "cpu":
+		return func(a, b ProcessStat) bool { return a.CPUPercent < b.CPUPercent }, nil
+	case "mem":
+		return func(a, b ProcessStat) bool { return a.MemoryBytes < b.MemoryBytes }, nil
+	case "read":
+		return func(a, b ProcessStat) bool { return a.ReadBytesPerSec < b.ReadBytesPerSec }, nil
+	case "write":
+		return func(a, b ProcessStat) bool { return a.WriteBytesPerSec < b.WriteBytesPerSec }, nil
+	case "fds":
+		return func(a, b ProcessStat) bool { return a.NumFDs < b.NumFDs }, nil
+	}
+	return nil, fmt.Errorf("unknown sort key %q (want one of %v)", key, ProcessSortKeys)
+}
@@ -0,0 +1,20 @@
+package hundler
+
+import "testing"
+
+func TestProcessLess(t *testing.T) {
+	a := ProcessStat{Pid: 1, Name: "b", CPUPercent: 5, MemoryBytes: 10, NumFDs: 3}
+	b := ProcessStat{Pid: 2, Name: "a", CPUPercent: 1, MemoryBytes: 20, NumFDs: 3}
+	for key, want := range map[string]bool{"pid": true, "name": false, "cpu": false, "mem": true, "fds": false} {
+		less, err := ProcessLess(key)
+		if err != nil {
+			t.Fatalf("ProcessLess(%q): %v", key, err)
+		}
+		if got := less(a, b); got != want {
+			t.Errorf("%s: less(a, b) = %v, want %v", key, got, want)
+		}
+	}
+	if _, err := ProcessLess("size"); err == nil {
+		t.Error("ProcessLess(\"size\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,131 @@
+package hundler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/common"
+)
+
+// userHZ is the unit of the clock tick counts in /proc/<pid>/stat. The kernel reports
+// them in USER_HZ, which is 100 on every architecture Linux supports today.
+const userHZ = 100
+
+// withProcRoot returns a context under which gopsutil reads from procRoot instead of
+// /proc or $HOST_PROC, so that what it reports matches the files read here directly.
+func withProcRoot(ctx context.Context, procRoot string) context.Context {
+	return context.WithValue(ctx, common.EnvKey, common.EnvMap{common.HostProcEnvKey: procRoot})
+}
+
+// processSample holds the fields of a process that change between refreshes. On Linux
+// they're all parsed out of /proc/<pid>/stat and /proc/<pid>/status, so a refresh costs
+// two reads per process instead of one per field.
+type processSample struct {
+	createTime int64 // Unix milliseconds
+	ppid       int32
+	uid        uint32
+	state      string
+	numThreads int32
+	nice       int32
+	cpuTime    float64 // user+system seconds
+	rss        uint64
+	vms        uint64
+
+	unavailable ProcessField // Fields that couldn't be read, see ProcessStat.Unavailable
+}
+
+// readProcessSample reads <procRoot>/<pid>/stat and status. bootTime is the system boot
+// time in seconds since the epoch, which process start times are relative to.
+func readProcessSample(procRoot string, pid int32, bootTime uint64) (processSample, error) {
+	dir := filepath.Join(procRoot, strconv.Itoa(int(pid)))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return processSample{}, err
+	}
+	s, err := parseProcStat(string(stat), bootTime)
+	if err != nil {
+		return processSample{}, fmt.Errorf("%s/stat: %w", dir, err)
+	}
+	status, err := os.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return processSample{}, err
+	}
+	if err := parseProcStatus(string(status), &s); err != nil {
+		return processSample{}, fmt.Errorf("%s/status: %w", dir, err)
+	}
+	return s, nil
+}
+
+// parseProcStat parses the single line of /proc/<pid>/stat. The command name in the
+// second field is wrapped in parentheses and may itself contain spaces and parentheses,
+// so the remaining fields are split off after the last closing parenthesis.
+func parseProcStat(data string, bootTime uint64) (processSample, error) {
+	end := strings.LastIndexByte(data, ')')
+	if end < 0 {
+		return processSample{}, fmt.Errorf("no command name in %q", data)
+	}
+	// fields[0] is the state, the third field described in proc(5).
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return processSample{}, fmt.Errorf("only %d fields after the command name", len(fields))
+	}
+	field := func(n int) (int64, error) {
+		return strconv.ParseInt(fields[n-3], 10, 64)
+	}
+
+	s := processSample{state: fields[0]}
+	var values [7]int64
+	for i, n := range []int{4, 14, 15, 19, 20, 22, 23} { // ppid, utime, stime, nice, num_threads, starttime, vsize
+		v, err := field(n)
+		if err != nil {
+			return processSample{}, err
+		}
+		values[i] = v
+	}
+	s.ppid = int32(values[0])
+	s.cpuTime = float64(values[1]+values[2]) / userHZ
+	s.nice = int32(values[3])
+	s.numThreads = int32(values[4])
+	s.createTime = int64(bootTime)*1000 + values[5]*1000/userHZ
+	s.vms = uint64(values[6])
+	return s, nil
+}
+
+// parseProcStatus fills in the owner and resident memory of s from /proc/<pid>/status.
+// Kernel threads have no memory lines, which leaves their memory at zero.
+func parseProcStatus(data string, s *processSample) error {
+	var haveUID bool
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		switch key {
+		case "Uid":
+			// Real, effective, saved and filesystem uid; ps shows the real one.
+			uid, err := strconv.ParseUint(fields[0], 10, 32)
+			if err != nil {
+				return err
+			}
+			s.uid, haveUID = uint32(uid), true
+		case "VmRSS":
+			kb, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			s.rss = kb * 1024
+		}
+	}
+	if !haveUID {
+		return fmt.Errorf("no Uid line")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package hundler
+
+import (
+	"os"
+	"testing"
+)
+
+func TestReadProcessSample(t *testing.T) {
+	const bootTime = 1_700_000_000
+	s, err := readProcessSample("testdata/proc", 4242, bootTime)
+	if err != nil {
+		t.Fatalf("readProcessSample() error: %v", err)
+	}
+	want := processSample{
+		createTime: (bootTime + 3600) * 1000,
+		ppid:       1,
+		uid:        1000,
+		state:      "S",
+		numThreads: 3,
+		nice:       5,
+		cpuTime:    15,
+		rss:        8000 * 1024,
+		vms:        120564 * 1024,
+	}
+	if s != want {
+		t.Errorf("sample = %+v, want %+v", s, want)
+	}
+
+	// Kernel threads have no memory of their own.
+	s, err = readProcessSample("testdata/proc", 2, bootTime)
+	if err != nil {
+		t.Fatalf("readProcessSample() error: %v", err)
+	}
+	if s.rss != 0 || s.vms != 0 || s.uid != 0 || s.ppid != 0 {
+		t.Errorf("kernel thread sample = %+v", s)
+	}
+
+	if _, err := readProcessSample("testdata/proc", 99999, bootTime); !os.IsNotExist(err) {
+		t.Errorf("exited process error = %v, want not exist", err)
+	}
+}
+
+func TestParseProcStatErrors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"42 (truncated",
+		"42 (short) S 1 42 42",
+		"42 (bad) S x 42 42 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 1 0 0",
+	} {
+		if _, err := parseProcStat(data, 0); err == nil {
+			t.Errorf("parseProcStat(%q) should fail", data)
+		}
+	}
+}
+
+func TestParseProcStatusWithoutUid(t *testing.T) {
+	var s processSample
+	if err := parseProcStatus("Name:\tx\nVmRSS:\t4 kB\n", &s); err == nil {
+		t.Error("status without a Uid line should fail")
+	}
+}
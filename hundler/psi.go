@@ -0,0 +1,176 @@
+package hundler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PressureLine holds one "some" or "full" line of a /proc/pressure file.
+type PressureLine struct {
+	Avg10  float64       // Percent of wall time stalled over the last 10 seconds
+	Avg60  float64       // Percent of wall time stalled over the last 60 seconds
+	Avg300 float64       // Percent of wall time stalled over the last 300 seconds
+	Total  time.Duration // Cumulative stall time since boot
+	Delta  time.Duration // Stall time accumulated since the previous sample
+}
+
+// PressureResource holds the pressure information of a single resource.
+// Available is false when the kernel doesn't expose PSI for the resource.
+type PressureResource struct {
+	Available bool
+	Some      PressureLine
+	Full      PressureLine
+	HasFull   bool
+}
+
+// PressureStat holds Pressure Stall Information for CPU, memory and IO.
+type PressureStat struct {
+	CPU    PressureResource
+	Memory PressureResource
+	IO     PressureResource
+}
+
+// parsePressureLine parses a line such as "some avg10=0.00 avg60=0.00 avg300=0.00 total=0".
+func parsePressureLine(line string) (kind string, p PressureLine, err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", p, fmt.Errorf("empty pressure line")
+	}
+	kind = fields[0]
+	for _, f := range fields[1:] {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok {
+			return "", p, fmt.Errorf("malformed pressure field %q", f)
+		}
+		switch key {
+		case "avg10", "avg60", "avg300":
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return "", p, fmt.Errorf("parsing %s: %w", key, err)
+			}
+			switch key {
+			case "avg10":
+				p.Avg10 = v
+			case "avg60":
+				p.Avg60 = v
+			case "avg300":
+				p.Avg300 = v
+			}
+		case "total":
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return "", p, fmt.Errorf("parsing total: %w", err)
+			}
+			p.Total = time.Duration(v) * time.Microsecond
+		}
+	}
+	return kind, p, nil
+}
+
+// readPressure reads and parses a single /proc/pressure/<resource> file.
+func readPressure(path string) (PressureResource, error) {
+	var r PressureResource
+	f, err := os.Open(path)
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		kind, p, err := parsePressureLine(scanner.Text())
+		if err != nil {
+			return r, err
+		}
+		switch kind {
+		case "some":
+			r.Some = p
+		case "full":
+			r.Full = p
+			r.HasFull = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return r, err
+	}
+	r.Available = true
+	return r, nil
+}
+
+// pressureDelta fills in the stall time accumulated since prev. A counter that went
+// backwards yields a zero delta.
+func pressureDelta(prev, cur PressureResource) PressureResource {
+	if !prev.Available || !cur.Available {
+		return cur
+	}
+	if cur.Some.Total >= prev.Some.Total {
+		cur.Some.Delta = cur.Some.Total - prev.Some.Total
+	}
+	if cur.HasFull && prev.HasFull && cur.Full.Total >= prev.Full.Total {
+		cur.Full.Delta = cur.Full.Total - prev.Full.Total
+	}
+	return cur
+}
+
+// ReadPressureStat reads the cpu, memory and io pressure files below procRoot (usually "/proc").
+// Resources whose files are missing or unreadable are reported with Available set to false.
+func ReadPressureStat(procRoot string) PressureStat {
+	read := func(name string) PressureResource {
+		r, err := readPressure(filepath.Join(procRoot, "pressure", name))
+		if err != nil {
+			return PressureResource{}
+		}
+		return r
+	}
+	return PressureStat{
+		CPU:    read("cpu"),
+		Memory: read("memory"),
+		IO:     read("io"),
+	}
+}
+
+// StartPressureMonitor starts a goroutine that periodically sends Pressure Stall Information
+// read from procRoot. The channel is closed when the provided context is cancelled.
+func StartPressureMonitor(ctx context.Context, interval time.Duration, procRoot string) <-chan PressureStat {
+	ch := make(chan PressureStat)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		prev := ReadPressureStat(procRoot)
+
+		for {
+			cur := ReadPressureStat(procRoot)
+			s := PressureStat{
+				CPU:    pressureDelta(prev.CPU, cur.CPU),
+				Memory: pressureDelta(prev.Memory, cur.Memory),
+				IO:     pressureDelta(prev.IO, cur.IO),
+			}
+			prev = cur
+
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticker.C:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,80 @@
+package hundler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReadPressureStat(t *testing.T) {
+	stat := ReadPressureStat("testdata/proc")
+
+	if !stat.CPU.Available || !stat.Memory.Available || !stat.IO.Available {
+		t.Fatalf("all resources should be available: %+v", stat)
+	}
+	if stat.CPU.Some.Avg10 != 4.39 || stat.CPU.Some.Avg60 != 5.19 || stat.CPU.Some.Avg300 != 2.44 {
+		t.Errorf("unexpected cpu some averages: %+v", stat.CPU.Some)
+	}
+	if stat.CPU.Some.Total != 15967935*time.Microsecond {
+		t.Errorf("cpu some total = %v, want %v", stat.CPU.Some.Total, 15967935*time.Microsecond)
+	}
+	if !stat.IO.HasFull || stat.IO.Full.Avg10 != 10.00 {
+		t.Errorf("unexpected io full line: %+v", stat.IO.Full)
+	}
+}
+
+func TestReadPressureStatMissing(t *testing.T) {
+	stat := ReadPressureStat(t.TempDir())
+	if stat.CPU.Available || stat.Memory.Available || stat.IO.Available {
+		t.Errorf("resources should be unavailable when pressure files are missing: %+v", stat)
+	}
+}
+
+func TestPressureDelta(t *testing.T) {
+	prev := PressureResource{Available: true, HasFull: true,
+		Some: PressureLine{Total: 100 * time.Microsecond},
+		Full: PressureLine{Total: 50 * time.Microsecond}}
+	cur := PressureResource{Available: true, HasFull: true,
+		Some: PressureLine{Total: 350 * time.Microsecond},
+		Full: PressureLine{Total: 20 * time.Microsecond}}
+
+	got := pressureDelta(prev, cur)
+	if got.Some.Delta != 250*time.Microsecond {
+		t.Errorf("some delta = %v, want %v", got.Some.Delta, 250*time.Microsecond)
+	}
+	if got.Full.Delta != 0 {
+		t.Errorf("full delta after counter reset = %v, want 0", got.Full.Delta)
+	}
+}
+
+func TestStartPressureMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interval := 100 * time.Millisecond
+	psiCh := StartPressureMonitor(ctx, interval, "testdata/proc")
+
+	// Test if at least one value is received
+	select {
+	case stat := <-psiCh:
+		if !stat.Memory.Available || stat.Memory.Full.Avg300 != 0.75 {
+			t.Errorf("unexpected memory pressure: %+v", stat.Memory)
+		}
+		if stat.Memory.Some.Delta != 0 {
+			t.Errorf("memory some delta should be 0 for unchanged fixtures: %v", stat.Memory.Some.Delta)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Timeout waiting for Pressure stats")
+	}
+
+	// Test if monitoring stops after context cancellation
+	cancel()
+	select {
+	case _, ok := <-psiCh:
+		if ok {
+			t.Error("Pressure channel should be closed after context cancellation")
+		}
+	case <-time.After(500 * time.Millisecond):
+		// This is acceptable
+	}
+}
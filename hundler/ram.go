@@ -7,10 +7,28 @@ import (
 	"github.com/shirou/gopsutil/v4/mem"
 )
 
+// RamStat holds periodic memory and swap usage information. On Linux, Used excludes
+// buffers and page cache, so Available is the better measure of how much memory is left.
 type RamStat struct {
 	Total       uint64
 	Used        uint64
 	UsedPercent float64
+	Available   uint64
+	Free        uint64
+	Buffers     uint64
+	Cached      uint64
+	Shared      uint64
+	Slab        uint64
+	Dirty       uint64
+	WriteBack   uint64
+
+	SwapTotal       uint64
+	SwapUsed        uint64
+	SwapUsedPercent float64
+	SwapInPerSec    float64 // Bytes swapped in per second
+	SwapOutPerSec   float64 // Bytes swapped out per second
+	TotalSwapIn     uint64  // Bytes swapped in since boot
+	TotalSwapOut    uint64  // Bytes swapped out since boot
 }
 
 func StartRamMonitor(ctx context.Context, interval time.Duration) <-chan RamStat {
@@ -20,11 +38,36 @@ func StartRamMonitor(ctx context.Context, interval time.Duration) <-chan RamStat
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
+		rates := newRateTracker()
+
 		for {
 			v, err := mem.VirtualMemory()
 			var s RamStat
 			if err == nil {
-				s = RamStat{Total: v.Total, Used: v.Used, UsedPercent: v.UsedPercent}
+				s = RamStat{
+					Total:       v.Total,
+					Used:        v.Used,
+					UsedPercent: v.UsedPercent,
+					Available:   v.Available,
+					Free:        v.Free,
+					Buffers:     v.Buffers,
+					Cached:      v.Cached,
+					Shared:      v.Shared,
+					Slab:        v.Slab,
+					Dirty:       v.Dirty,
+					WriteBack:   v.WriteBack,
+				}
+			}
+
+			if sw, err := mem.SwapMemory(); err == nil {
+				r, _, _ := rates.update("swap", time.Now(), sw.Sin, sw.Sout)
+				s.SwapTotal = sw.Total
+				s.SwapUsed = sw.Used
+				s.SwapUsedPercent = sw.UsedPercent
+				s.SwapInPerSec = r[0]
+				s.SwapOutPerSec = r[1]
+				s.TotalSwapIn = sw.Sin
+				s.TotalSwapOut = sw.Sout
 			}
 
 			select {
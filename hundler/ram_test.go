@@ -22,6 +22,15 @@ func TestStartRamMonitor(t *testing.T) {
 		if stat.UsedPercent < 0 || stat.UsedPercent > 100 {
 			t.Errorf("RamStat.UsedPercent out of range: %f", stat.UsedPercent)
 		}
+		if stat.Available > stat.Total {
+			t.Errorf("RamStat.Available (%d) should not exceed Total (%d)", stat.Available, stat.Total)
+		}
+		if stat.SwapUsed > stat.SwapTotal {
+			t.Errorf("RamStat.SwapUsed (%d) should not exceed SwapTotal (%d)", stat.SwapUsed, stat.SwapTotal)
+		}
+		if stat.SwapInPerSec < 0 || stat.SwapOutPerSec < 0 {
+			t.Errorf("RamStat swap rates should not be negative: in %f, out %f", stat.SwapInPerSec, stat.SwapOutPerSec)
+		}
 	case <-time.After(500 * time.Millisecond):
 		t.Fatal("Timeout waiting for RAM stats")
 	}
@@ -0,0 +1,70 @@
+package hundler
+
+import "time"
+
+// rateSample is the last set of counter values seen for a key and when they were read.
+type rateSample struct {
+	at     time.Time
+	values []uint64
+}
+
+// rateTracker turns successive readings of cumulative counters into per-second rates.
+//
+// Rates are divided by the time actually elapsed between two readings, measured on the
+// monotonic clock, rather than by the nominal refresh interval, so a drifting ticker or a
+// consumer that is slow to drain the channel doesn't skew them. A key whose counters went
+// backwards (counter wrap, device or interface re-created) is re-baselined instead of
+// producing a huge spike.
+type rateTracker struct {
+	last map[string]rateSample
+	seen map[string]bool
+}
+
+func newRateTracker() *rateTracker {
+	return &rateTracker{
+		last: make(map[string]rateSample),
+		seen: make(map[string]bool),
+	}
+}
+
+// update records the counter values read for key at time at and returns the per-second
+// rate of each value since the previous reading. ok is false when there is no usable
+// previous reading: the key is new, no time has elapsed, the number of values changed or
+// any counter went backwards. In that case the returned rates are all zero.
+func (t *rateTracker) update(key string, at time.Time, values ...uint64) (rates []float64, elapsed time.Duration, ok bool) {
+	rates = make([]float64, len(values))
+	t.seen[key] = true
+
+	prev, found := t.last[key]
+	t.last[key] = rateSample{at: at, values: append([]uint64(nil), values...)}
+	if !found || len(prev.values) != len(values) {
+		return rates, 0, false
+	}
+
+	elapsed = at.Sub(prev.at)
+	if elapsed <= 0 {
+		return rates, 0, false
+	}
+	for i, v := range values {
+		if v < prev.values[i] {
+			return rates, 0, false
+		}
+	}
+
+	secs := elapsed.Seconds()
+	for i, v := range values {
+		rates[i] = float64(v-prev.values[i]) / secs
+	}
+	return rates, elapsed, true
+}
+
+// prune forgets every key that wasn't updated since the previous call to prune, so that
+// devices or interfaces that disappeared don't accumulate.
+func (t *rateTracker) prune() {
+	for key := range t.last {
+		if !t.seen[key] {
+			delete(t.last, key)
+		}
+	}
+	t.seen = make(map[string]bool)
+}
@@ -0,0 +1,71 @@
+package hundler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateTracker(t *testing.T) {
+	start := time.Now()
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	steps := []struct {
+		name   string
+		at     time.Time
+		values []uint64
+		want   []float64
+		ok     bool
+	}{
+		{"first sample has no baseline", at(0), []uint64{1000, 500}, []float64{0, 0}, false},
+		{"steady one second interval", at(1000), []uint64{3000, 1500}, []float64{2000, 1000}, true},
+		{"late sample uses measured elapsed time", at(3000), []uint64{7000, 1500}, []float64{2000, 0}, true},
+		{"counter reset is re-baselined", at(4000), []uint64{100, 1600}, []float64{0, 0}, false},
+		{"rates resume after reset", at(4500), []uint64{600, 1700}, []float64{1000, 200}, true},
+		{"no elapsed time", at(4500), []uint64{700, 1800}, []float64{0, 0}, false},
+	}
+
+	tr := newRateTracker()
+	for _, step := range steps {
+		rates, _, ok := tr.update("eth0", step.at, step.values...)
+		if ok != step.ok {
+			t.Errorf("%s: ok = %v, want %v", step.name, ok, step.ok)
+		}
+		for i := range step.want {
+			if rates[i] != step.want[i] {
+				t.Errorf("%s: rates[%d] = %f, want %f", step.name, i, rates[i], step.want[i])
+			}
+		}
+	}
+}
+
+func TestRateTrackerWrap(t *testing.T) {
+	start := time.Now()
+	tr := newRateTracker()
+
+	// A 32-bit counter wrapping around must not be reported as an enormous rate.
+	tr.update("eth0", start, 4294967000)
+	rates, _, ok := tr.update("eth0", start.Add(time.Second), 200)
+	if ok || rates[0] != 0 {
+		t.Errorf("wrapped counter: rates = %v, ok = %v, want zero rate and ok false", rates, ok)
+	}
+}
+
+func TestRateTrackerPrune(t *testing.T) {
+	start := time.Now()
+	tr := newRateTracker()
+
+	tr.update("eth0", start, 100)
+	tr.update("veth1", start, 100)
+	tr.prune()
+
+	// veth1 disappears for one round and is forgotten.
+	tr.update("eth0", start.Add(time.Second), 200)
+	tr.prune()
+
+	if _, _, ok := tr.update("veth1", start.Add(2*time.Second), 5000); ok {
+		t.Error("pruned key should start from a fresh baseline")
+	}
+	if rates, _, ok := tr.update("eth0", start.Add(2*time.Second), 300); !ok || rates[0] != 100 {
+		t.Errorf("eth0 rates = %v, ok = %v, want [100] and ok true", rates, ok)
+	}
+}
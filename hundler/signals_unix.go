@@ -0,0 +1,15 @@
+//go:build !windows
+
+package hundler
+
+import "syscall"
+
+// Signals lists the signals offered in the TUI signal picker, most common first.
+var Signals = []NamedSignal{
+	{"SIGTERM", syscall.SIGTERM},
+	{"SIGKILL", syscall.SIGKILL},
+	{"SIGSTOP", syscall.SIGSTOP},
+	{"SIGCONT", syscall.SIGCONT},
+	{"SIGHUP", syscall.SIGHUP},
+	{"SIGINT", syscall.SIGINT},
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package hundler
+
+import "syscall"
+
+// Signals lists the signals offered in the TUI signal picker. Windows can only
+// terminate processes, so stop/continue/hangup aren't available.
+var Signals = []NamedSignal{
+	{"SIGTERM", syscall.SIGTERM},
+	{"SIGKILL", syscall.SIGKILL},
+	{"SIGINT", syscall.SIGINT},
+}
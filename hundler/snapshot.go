@@ -0,0 +1,100 @@
+package hundler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Monitors holds the channels of a running set of monitors.
+type Monitors struct {
+	Cpu        <-chan CpuStat
+	Ram        <-chan RamStat
+	Disks      <-chan []DiskStat
+	DiskIO     <-chan []DiskIOStat
+	Net        <-chan NetStat
+	Interfaces <-chan []InterfaceStat
+	Load       <-chan LoadStat
+	Pressure   <-chan PressureStat
+	Processes  <-chan []ProcessStat
+}
+
+// Snapshot holds the latest value received from every monitor.
+type Snapshot struct {
+	Time       time.Time // When the snapshot was taken
+	Cpu        CpuStat
+	Ram        RamStat
+	Disks      []DiskStat
+	DiskIO     []DiskIOStat
+	Net        NetStat
+	Interfaces []InterfaceStat
+	Load       LoadStat
+	Pressure   PressureStat
+	Processes  []ProcessStat
+}
+
+// SnapshotStore keeps the latest value of every monitor so that consumers which don't
+// follow the channels themselves, such as batch output, can read them at their own pace.
+type SnapshotStore struct {
+	mu   sync.Mutex
+	last Snapshot
+	now  func() time.Time
+}
+
+// WatchMonitors starts a goroutine that drains every channel of m into the returned store.
+// It stops when the provided context is cancelled or every channel is closed.
+func WatchMonitors(ctx context.Context, m Monitors) *SnapshotStore {
+	s := &SnapshotStore{now: time.Now}
+	go s.watch(ctx, m)
+	return s
+}
+
+func (s *SnapshotStore) watch(ctx context.Context, m Monitors) {
+	for m.Cpu != nil || m.Ram != nil || m.Disks != nil || m.DiskIO != nil || m.Net != nil ||
+		m.Interfaces != nil || m.Load != nil || m.Pressure != nil || m.Processes != nil {
+		// A closed channel is set to nil so the select stops picking it.
+		select {
+		case v, ok := <-m.Cpu:
+			s.update(ok, func(snap *Snapshot) { snap.Cpu = v }, func() { m.Cpu = nil })
+		case v, ok := <-m.Ram:
+			s.update(ok, func(snap *Snapshot) { snap.Ram = v }, func() { m.Ram = nil })
+		case v, ok := <-m.Disks:
+			s.update(ok, func(snap *Snapshot) { snap.Disks = v }, func() { m.Disks = nil })
+		case v, ok := <-m.DiskIO:
+			s.update(ok, func(snap *Snapshot) { snap.DiskIO = v }, func() { m.DiskIO = nil })
+		case v, ok := <-m.Net:
+			s.update(ok, func(snap *Snapshot) { snap.Net = v }, func() { m.Net = nil })
+		case v, ok := <-m.Interfaces:
+			s.update(ok, func(snap *Snapshot) { snap.Interfaces = v }, func() { m.Interfaces = nil })
+		case v, ok := <-m.Load:
+			s.update(ok, func(snap *Snapshot) { snap.Load = v }, func() { m.Load = nil })
+		case v, ok := <-m.Pressure:
+			s.update(ok, func(snap *Snapshot) { snap.Pressure = v }, func() { m.Pressure = nil })
+		case v, ok := <-m.Processes:
+			s.update(ok, func(snap *Snapshot) { snap.Processes = v }, func() { m.Processes = nil })
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// update applies set to the stored snapshot if a value was received, or calls closed.
+func (s *SnapshotStore) update(ok bool, set func(*Snapshot), closed func()) {
+	if !ok {
+		closed()
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set(&s.last)
+}
+
+// Snapshot returns the latest values, stamped with the current time. Slices are shared
+// with the store but never modified by it, as every update replaces them.
+func (s *SnapshotStore) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := s.last
+	snap.Time = s.now()
+	return snap
+}
@@ -0,0 +1,42 @@
+package hundler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchMonitors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cpuCh := make(chan CpuStat)
+	procCh := make(chan []ProcessStat)
+	store := WatchMonitors(ctx, Monitors{Cpu: cpuCh, Processes: procCh})
+
+	cpuCh <- CpuStat{Percent: 42}
+	procCh <- []ProcessStat{{Pid: 1, Name: "init"}}
+	// The next send only completes once the previous values were stored.
+	cpuCh <- CpuStat{Percent: 43}
+	close(procCh)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		snap := store.Snapshot()
+		if snap.Cpu.Percent == 43 && len(snap.Processes) == 1 && !snap.Time.IsZero() {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("snapshot never caught up: %+v", snap)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// A closed channel keeps its last value and doesn't stop the others.
+	cpuCh <- CpuStat{Percent: 44}
+	time.Sleep(50 * time.Millisecond)
+	if snap := store.Snapshot(); snap.Cpu.Percent != 44 || len(snap.Processes) != 1 {
+		t.Errorf("after close: cpu %.0f, %d processes", snap.Cpu.Percent, len(snap.Processes))
+	}
+}
@@ -1,31 +1,73 @@
 package main
 
 import (
+	"basicsystemmonitor/encoder"
 	"basicsystemmonitor/hundler"
+	"basicsystemmonitor/server"
 	"basicsystemmonitor/tui"
 	"context"
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// stringList is a flag.Value that collects every occurrence of a repeatable flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func main() {
+	// "serve" runs the HTTP server instead of the interactive UI; flags follow it.
+	serve := len(os.Args) > 1 && os.Args[1] == "serve"
+	if serve {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
 	var configPath string
 	var refreshIntervalStr string
-	var diskPathStr string
+	var diskPaths stringList
+	var diskDiscover bool
 	var ifaceName string
-	var showProcesses bool // New: for process list visibility
+	var showProcesses bool               // New: for process list visibility
 	var processRefreshIntervalStr string // New: for process refresh interval
+	var processColumnsStr string
+	var processFilterStr string
+	var batch bool
+	var iterations int
+	var format string
+	var topProcesses int
+	var listenAddress string
 
 	flag.StringVar(&configPath, "c", "config.yaml", "Path to configuration file")
 	flag.StringVar(&refreshIntervalStr, "i", "", "Refresh interval (e.g., 1s, 500ms)")
-	flag.StringVar(&diskPathStr, "d", "", "Disk path to monitor (e.g., /var, C:\\)")
+	flag.Var(&diskPaths, "d", "Disk path to monitor, may be repeated (e.g., -d / -d /var)")
+	flag.BoolVar(&diskDiscover, "all-disks", false, "Monitor every real mounted filesystem")
 	flag.StringVar(&ifaceName, "iface", "", "Network interface to monitor (e.g., eth0, en0)")
-	flag.BoolVar(&showProcesses, "p", false, "Show process list") // New flag
+	flag.BoolVar(&showProcesses, "p", false, "Show process list")                                                   // New flag
 	flag.StringVar(&processRefreshIntervalStr, "proc-interval", "", "Process list refresh interval (e.g., 3s, 5s)") // New flag
+	flag.StringVar(&processColumnsStr, "columns", "", "Comma-separated process table columns (e.g., pid,user,name,cpu,mem)")
+	flag.StringVar(&processFilterStr, "filter", "", "Initial process filter (e.g., \"user:postgres cpu>5\")")
+	flag.BoolVar(&batch, "batch", false, "Print plain-text snapshots to stdout instead of starting the interactive UI")
+	flag.IntVar(&iterations, "n", 0, "Number of snapshots to print in batch mode, 0 for no limit")
+	flag.StringVar(&format, "format", "text", "Batch output format: text, json (JSON Lines) or csv; json and csv imply -batch")
+	flag.IntVar(&topProcesses, "top", 0, "Number of busiest processes in each json or csv record")
+	flag.StringVar(&listenAddress, "listen", "", "HTTP listen address of the serve mode (e.g., :9184, 127.0.0.1:9184)")
 	flag.Parse()
 
 	config, err := LoadConfig(configPath)
@@ -37,12 +79,30 @@ func main() {
 	if refreshIntervalStr != "" {
 		config.RefreshInterval = refreshIntervalStr
 	}
-	if diskPathStr != "" {
-		config.DiskPath = diskPathStr
+	if len(diskPaths) > 0 {
+		config.DiskPaths = diskPaths
+	}
+	if diskDiscover {
+		config.DiskDiscover = true
 	}
 	if processRefreshIntervalStr != "" {
 		config.ProcessRefreshInterval = processRefreshIntervalStr
 	}
+	if processColumnsStr != "" {
+		config.ProcessColumns = strings.Split(processColumnsStr, ",")
+	}
+	if err := tui.ValidateProcessColumns(config.ProcessColumns); err != nil {
+		log.Fatalf("Error in process columns: %v", err)
+	}
+	if listenAddress != "" {
+		config.ListenAddress = listenAddress
+	}
+	if processFilterStr != "" {
+		config.ProcessFilter = processFilterStr
+	}
+	if _, err := hundler.ParseProcessFilter(config.ProcessFilter); err != nil {
+		log.Fatalf("Error in process filter: %v", err)
+	}
 
 	refreshInterval, err := config.GetRefreshInterval()
 	if err != nil {
@@ -57,15 +117,51 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Start monitors and get their channels
-	cpuCh := hundler.StartCpuMonitor(ctx, refreshInterval)
-	ramCh := hundler.StartRamMonitor(ctx, refreshInterval)
-	diskCh := hundler.StartDiskMonitor(ctx, 2*refreshInterval, config.DiskPath)
-	netCh := hundler.StartNetworkMonitor(ctx, refreshInterval, ifaceName) // Pass ifaceName
-	procCh := hundler.StartProcessMonitor(ctx, processRefreshInterval) // Use new interval
+	monitors := startMonitors(ctx, config, ifaceName, refreshInterval, processRefreshInterval)
+	opts := tui.Options{
+		IfaceName:     ifaceName,
+		ShowProcesses: showProcesses,
+		DiskAlerts: hundler.DiskThresholds{
+			UsedPercent:       config.DiskAlertPercent,
+			InodesUsedPercent: config.InodeAlertPercent,
+		},
+		ProcessColumns: config.ProcessColumns,
+		ProcessFilter:  config.ProcessFilter,
+		DetailMonitor: func(ctx context.Context, pid int32) <-chan hundler.ProcessDetail {
+			return hundler.StartProcessDetailMonitor(ctx, processRefreshInterval, pid, config.ProcRoot)
+		},
+	}
+
+	if serve {
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := runServer(ctx, config.ListenAddress, hundler.WatchMonitors(ctx, monitors), refreshInterval); err != nil {
+			log.Fatalf("Error serving HTTP: %v", err)
+		}
+		return
+	}
+
+	if batch || format != "text" {
+		if iterations < 0 {
+			log.Fatalf("Error in -n: %d iterations", iterations)
+		}
+		var enc encoder.Encoder = &textEncoder{w: os.Stdout, opts: opts}
+		if format != "text" {
+			if enc, err = encoder.New(format, os.Stdout, encoder.Options{TopProcesses: topProcesses}); err != nil {
+				log.Fatalf("Error in -format: %v", err)
+			}
+		}
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		store := hundler.WatchMonitors(ctx, monitors)
+		if err := runBatch(ctx, store, refreshInterval, iterations, enc); err != nil {
+			log.Fatalf("Error writing snapshot: %v", err)
+		}
+		return
+	}
 
 	// Initialize the Bubble Tea model with the channels
-	initialModel := tui.New(cpuCh, ramCh, diskCh, netCh, procCh, ifaceName, showProcesses)
+	initialModel := tui.New(monitors.Cpu, monitors.Ram, monitors.Disks, monitors.DiskIO, monitors.Net, monitors.Interfaces, monitors.Load, monitors.Pressure, monitors.Processes, opts)
 
 	// Start the Bubble Tea program
 	p := tea.NewProgram(initialModel)
@@ -74,3 +170,44 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// startMonitors starts every monitor with the configured settings.
+func startMonitors(ctx context.Context, config Config, ifaceName string, refreshInterval, processRefreshInterval time.Duration) hundler.Monitors {
+	return hundler.Monitors{
+		Cpu: hundler.StartCpuMonitor(ctx, refreshInterval),
+		Ram: hundler.StartRamMonitor(ctx, refreshInterval),
+		Disks: hundler.StartDiskMonitor(ctx, 2*refreshInterval, hundler.DiskOptions{
+			Paths:         config.GetDiskPaths(),
+			Discover:      config.DiskDiscover,
+			IgnoreFsTypes: config.DiskIgnoreFsTypes,
+		}),
+		Net:        hundler.StartNetworkMonitor(ctx, refreshInterval, ifaceName),
+		Interfaces: hundler.StartInterfaceMonitor(ctx, refreshInterval, config.NetInterfaces, config.NetInterfacesExclude),
+		Load:       hundler.StartLoadMonitor(ctx, refreshInterval),
+		Pressure:   hundler.StartPressureMonitor(ctx, refreshInterval, config.ProcRoot),
+		DiskIO:     hundler.StartDiskIOMonitor(ctx, refreshInterval, config.DiskDevices, config.DiskDevicesExclude),
+		Processes:  hundler.StartProcessMonitor(ctx, processRefreshInterval, config.ProcRoot),
+	}
+}
+
+// runServer serves the snapshots of store over HTTP on addr until ctx is cancelled.
+func runServer(ctx context.Context, addr string, store *hundler.SnapshotStore, refreshInterval time.Duration) error {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: server.New(store, server.Options{RefreshInterval: refreshInterval}),
+		// Requests inherit ctx so that open event streams end on shutdown.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+	log.Printf("Serving metrics and the API on %s", addr)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
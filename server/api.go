@@ -0,0 +1,140 @@
+package server
+
+import (
+	"basicsystemmonitor/encoder"
+	"basicsystemmonitor/hundler"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// writeJSON writes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing JSON response: %v", err)
+	}
+}
+
+// writeError writes an error response as {"error": "..."}.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// record returns the latest snapshot in the versioned schema of the encoder package,
+// with every process, busiest first.
+func (s *server) record() encoder.Record {
+	snap := s.store.Snapshot()
+	r := encoder.NewRecord(snap, encoder.Options{Hostname: s.opts.Hostname, TopProcesses: len(snap.Processes)})
+	if r.Processes == nil {
+		r.Processes = []encoder.Process{}
+	}
+	return r
+}
+
+// handleSnapshot serves the latest full snapshot.
+func (s *server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.record())
+}
+
+// handleProcesses serves the latest processes, narrowed and ordered by the query:
+//
+//	sort    one of hundler.ProcessSortKeys, default cpu
+//	order   asc or desc, default desc
+//	filter  a process filter expression, see hundler.ProcessFilter
+//	limit   the maximum number of processes, default all
+func (s *server) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	key := q.Get("sort")
+	if key == "" {
+		key = "cpu"
+	}
+	less, err := hundler.ProcessLess(key)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch q.Get("order") {
+	case "", "desc":
+		asc := less
+		less = func(a, b hundler.ProcessStat) bool { return asc(b, a) }
+	case "asc":
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown order %q (want asc or desc)", q.Get("order")))
+		return
+	}
+	filter, err := hundler.ParseProcessFilter(q.Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+	}
+
+	// The store shares its slice, so sort a copy.
+	procs := slices.Clone(filter.Apply(s.store.Snapshot().Processes))
+	slices.SortStableFunc(procs, func(a, b hundler.ProcessStat) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+	if limit > 0 && limit < len(procs) {
+		procs = procs[:limit]
+	}
+	records := make([]encoder.Process, 0, len(procs))
+	for _, p := range procs {
+		records = append(records, encoder.NewProcess(p))
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+// handleStream sends the latest snapshot as a Server-Sent Event every refresh interval
+// until the client disconnects. Every client reads the same store, so the collectors
+// are shared no matter how many are connected.
+func (s *server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	// Tell EventSource clients to reconnect at the pace of the updates.
+	fmt.Fprintf(w, "retry: %d\n\n", s.opts.RefreshInterval.Milliseconds())
+
+	ticker := time.NewTicker(s.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		data, err := json.Marshal(s.record())
+		if err != nil {
+			log.Printf("Error encoding snapshot: %v", err)
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", data); err != nil {
+			// The client went away.
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"basicsystemmonitor/encoder"
+	"basicsystemmonitor/hundler"
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func apiSnapshot() hundler.Snapshot {
+	return hundler.Snapshot{
+		Cpu: hundler.CpuStat{Percent: 25},
+		Processes: []hundler.ProcessStat{
+			{Pid: 1, Name: "init", Username: "root", CPUPercent: 0.5, MemoryBytes: 8 << 20},
+			{Pid: 200, Name: "postgres", Username: "postgres", CPUPercent: 30, MemoryBytes: 512 << 20},
+			{Pid: 201, Name: "postgres", Username: "postgres", CPUPercent: 12, MemoryBytes: 256 << 20},
+			{Pid: 300, Name: "bash", Username: "alice", CPUPercent: 1, MemoryBytes: 4 << 20},
+		},
+	}
+}
+
+func TestSnapshotEndpoint(t *testing.T) {
+	srv := httptest.NewServer(New(newTestStore(t, apiSnapshot()), Options{}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var got encoder.Record
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Schema != encoder.SchemaVersion || got.Timestamp == "" || got.Hostname == "" {
+		t.Errorf("unexpected header fields: %+v", got)
+	}
+	if got.CPU.Percent != 25 || len(got.Processes) != 4 || got.Processes[0].Pid != 200 {
+		t.Errorf("record = %+v, want every process, busiest first", got)
+	}
+}
+
+func TestProcessesEndpoint(t *testing.T) {
+	srv := httptest.NewServer(New(newTestStore(t, apiSnapshot()), Options{}))
+	defer srv.Close()
+
+	tests := []struct {
+		query string
+		pids  []int32
+	}{
+		{"", []int32{200, 201, 300, 1}},
+		{"?sort=mem&order=asc", []int32{300, 1, 201, 200}},
+		{"?sort=pid&limit=2", []int32{300, 201}},
+		{"?filter=user:postgres+cpu>20", []int32{200}},
+		{"?filter=user:nobody", []int32{}},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + "/api/v1/processes" + tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var procs []encoder.Process
+		err = json.NewDecoder(resp.Body).Decode(&procs)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("%q: status %d, %v", tt.query, resp.StatusCode, err)
+		}
+		var pids []int32
+		for _, p := range procs {
+			pids = append(pids, p.Pid)
+		}
+		if !slices.Equal(pids, tt.pids) {
+			t.Errorf("%q = %v, want %v", tt.query, pids, tt.pids)
+		}
+	}
+
+	for _, query := range []string{"?sort=size", "?order=up", "?filter=cpu>lots", "?limit=-1"} {
+		resp, err := http.Get(srv.URL + "/api/v1/processes" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct{ Error string }
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || body.Error == "" {
+			t.Errorf("%q: status %d, error %q, want 400 with an error", query, resp.StatusCode, body.Error)
+		}
+	}
+}
+
+func TestStreamEndpoint(t *testing.T) {
+	srv := httptest.NewServer(New(newTestStore(t, apiSnapshot()), Options{RefreshInterval: 10 * time.Millisecond}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type %q", ct)
+	}
+
+	// Read two events to see the stream repeat.
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+	events := 0
+	event := ""
+	for events < 2 && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if event != "snapshot" {
+				t.Fatalf("data for event %q", event)
+			}
+			var s encoder.Record
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &s); err != nil {
+				t.Fatal(err)
+			}
+			if len(s.Processes) != 4 {
+				t.Errorf("event has %d processes, want 4", len(s.Processes))
+			}
+			events++
+		}
+	}
+	if events < 2 {
+		t.Fatalf("got %d events before the stream ended: %v", events, scanner.Err())
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"basicsystemmonitor/hundler"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metricPrefix namespaces every exported metric.
+const metricPrefix = "sysmon_"
+
+// metricWriter builds a Prometheus text exposition (format version 0.0.4).
+type metricWriter struct {
+	b strings.Builder
+}
+
+// family starts a metric family with its help text and type, "gauge" or "counter".
+func (m *metricWriter) family(name, typ, help string) {
+	m.b.WriteString("# HELP " + metricPrefix + name + " " + escapeHelp(help) + "\n")
+	m.b.WriteString("# TYPE " + metricPrefix + name + " " + typ + "\n")
+}
+
+// sample writes one sample of the current family. labels are name, value pairs.
+func (m *metricWriter) sample(name string, value float64, labels ...string) {
+	m.b.WriteString(metricPrefix + name)
+	if len(labels) > 0 {
+		m.b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				m.b.WriteByte(',')
+			}
+			m.b.WriteString(labels[i] + `="` + escapeLabel(labels[i+1]) + `"`)
+		}
+		m.b.WriteByte('}')
+	}
+	m.b.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+// gauge writes a family with a single unlabelled gauge sample.
+func (m *metricWriter) gauge(name, help string, value float64) {
+	m.family(name, "gauge", help)
+	m.sample(name, value)
+}
+
+// counter writes a counter family with a single unlabelled sample.
+func (m *metricWriter) counter(name, help string, value float64) {
+	m.family(name, "counter", help)
+	m.sample(name, value)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// WriteMetrics writes a snapshot in the Prometheus text exposition format. Usage is
+// exported in base units: ratios from 0 to 1 rather than percentages, bytes and seconds.
+func WriteMetrics(w io.Writer, s hundler.Snapshot) error {
+	var m metricWriter
+
+	m.gauge("cpu_usage_ratio", "Fraction of CPU time spent busy across all CPUs.", s.Cpu.Percent/100)
+	m.family("cpu_core_usage_ratio", "gauge", "Fraction of time each CPU spent busy.")
+	for i, p := range s.Cpu.PerCore {
+		m.sample("cpu_core_usage_ratio", p/100, "cpu", strconv.Itoa(i))
+	}
+	m.family("cpu_mode_ratio", "gauge", "Fraction of CPU time spent in each mode across all CPUs.")
+	t := s.Cpu.Times
+	for _, mode := range []struct {
+		name  string
+		value float64
+	}{
+		{"user", t.User}, {"nice", t.Nice}, {"system", t.System}, {"idle", t.Idle}, {"iowait", t.Iowait},
+		{"irq", t.Irq}, {"softirq", t.Softirq}, {"steal", t.Steal}, {"guest", t.Guest}, {"guest_nice", t.GuestNice},
+	} {
+		m.sample("cpu_mode_ratio", mode.value/100, "mode", mode.name)
+	}
+
+	m.gauge("load1", "1-minute load average.", s.Load.Load1)
+	m.gauge("load5", "5-minute load average.", s.Load.Load5)
+	m.gauge("load15", "15-minute load average.", s.Load.Load15)
+	m.gauge("procs_running", "Number of runnable tasks.", float64(s.Load.ProcsRunning))
+	m.gauge("procs_blocked", "Number of tasks blocked on I/O.", float64(s.Load.ProcsBlocked))
+	if !s.Load.BootTime.IsZero() {
+		m.gauge("boot_time_seconds", "System boot time in seconds since the Unix epoch.", float64(s.Load.BootTime.Unix()))
+	}
+
+	r := s.Ram
+	m.gauge("memory_total_bytes", "Total physical memory.", float64(r.Total))
+	m.gauge("memory_used_bytes", "Physical memory in use.", float64(r.Used))
+	m.gauge("memory_available_bytes", "Memory available for new allocations without swapping.", float64(r.Available))
+	m.gauge("memory_free_bytes", "Unused physical memory.", float64(r.Free))
+	m.gauge("memory_buffers_bytes", "Memory used for block device buffers.", float64(r.Buffers))
+	m.gauge("memory_cached_bytes", "Memory used for the page cache.", float64(r.Cached))
+	m.gauge("memory_shared_bytes", "Memory used by shared memory and tmpfs.", float64(r.Shared))
+	m.gauge("swap_total_bytes", "Total swap space.", float64(r.SwapTotal))
+	m.gauge("swap_used_bytes", "Swap space in use.", float64(r.SwapUsed))
+	m.counter("swap_in_bytes_total", "Memory swapped in.", float64(r.TotalSwapIn))
+	m.counter("swap_out_bytes_total", "Memory swapped out.", float64(r.TotalSwapOut))
+
+	filesystems := []struct {
+		name, help string
+		value      func(d hundler.DiskStat) uint64
+	}{
+		{"filesystem_size_bytes", "Filesystem size.", func(d hundler.DiskStat) uint64 { return d.Total }},
+		{"filesystem_used_bytes", "Filesystem space in use.", func(d hundler.DiskStat) uint64 { return d.Used }},
+		{"filesystem_free_bytes", "Filesystem space available to unprivileged users.", func(d hundler.DiskStat) uint64 { return d.Free }},
+		{"filesystem_inodes", "Total inodes of the filesystem.", func(d hundler.DiskStat) uint64 { return d.InodesTotal }},
+		{"filesystem_inodes_free", "Free inodes of the filesystem.", func(d hundler.DiskStat) uint64 { return d.InodesFree }},
+	}
+	for _, f := range filesystems {
+		m.family(f.name, "gauge", f.help)
+		for _, d := range s.Disks {
+			m.sample(f.name, float64(f.value(d)), "mountpoint", d.Path, "device", d.Device, "fstype", d.Fstype)
+		}
+	}
+
+	// Rates are left to rate() in PromQL, so that they don't depend on the refresh interval.
+	diskIO := []struct {
+		name, help string
+		value      func(d hundler.DiskIOStat) float64
+	}{
+		{"disk_read_bytes_total", "Bytes read from the device.", func(d hundler.DiskIOStat) float64 { return float64(d.TotalReadBytes) }},
+		{"disk_written_bytes_total", "Bytes written to the device.", func(d hundler.DiskIOStat) float64 { return float64(d.TotalWriteBytes) }},
+		{"disk_reads_completed_total", "Completed reads.", func(d hundler.DiskIOStat) float64 { return float64(d.TotalReads) }},
+		{"disk_writes_completed_total", "Completed writes.", func(d hundler.DiskIOStat) float64 { return float64(d.TotalWrites) }},
+		{"disk_read_time_seconds_total", "Time spent on reads, including queueing.", func(d hundler.DiskIOStat) float64 { return d.TotalReadTime.Seconds() }},
+		{"disk_write_time_seconds_total", "Time spent on writes, including queueing.", func(d hundler.DiskIOStat) float64 { return d.TotalWriteTime.Seconds() }},
+		{"disk_io_time_seconds_total", "Time the device had requests in flight.", func(d hundler.DiskIOStat) float64 { return d.TotalIoTime.Seconds() }},
+	}
+	for _, f := range diskIO {
+		m.family(f.name, "counter", f.help)
+		for _, d := range s.DiskIO {
+			m.sample(f.name, f.value(d), "device", d.Name)
+		}
+	}
+
+	network := []struct {
+		name, help string
+		value      func(i hundler.InterfaceStat) uint64
+	}{
+		{"network_transmit_bytes_total", "Bytes sent by the interface.", func(i hundler.InterfaceStat) uint64 { return i.TotalBytesSent }},
+		{"network_receive_bytes_total", "Bytes received by the interface.", func(i hundler.InterfaceStat) uint64 { return i.TotalBytesRecv }},
+		{"network_transmit_packets_total", "Packets sent.", func(i hundler.InterfaceStat) uint64 { return i.TotalPacketsSent }},
+		{"network_receive_packets_total", "Packets received.", func(i hundler.InterfaceStat) uint64 { return i.TotalPacketsRecv }},
+		{"network_transmit_errors_total", "Transmit errors.", func(i hundler.InterfaceStat) uint64 { return i.TotalErrout }},
+		{"network_receive_errors_total", "Receive errors.", func(i hundler.InterfaceStat) uint64 { return i.TotalErrin }},
+		{"network_transmit_drops_total", "Outgoing packets dropped.", func(i hundler.InterfaceStat) uint64 { return i.TotalDropout }},
+		{"network_receive_drops_total", "Incoming packets dropped.", func(i hundler.InterfaceStat) uint64 { return i.TotalDropin }},
+	}
+	for _, f := range network {
+		m.family(f.name, "counter", f.help)
+		for _, i := range s.Interfaces {
+			m.sample(f.name, float64(f.value(i)), "interface", i.Name)
+		}
+	}
+
+	m.family("pressure_stalled_seconds_total", "counter", "Time tasks were stalled waiting for the resource (Linux PSI).")
+	for _, p := range pressureResources(s.Pressure) {
+		m.sample("pressure_stalled_seconds_total", p.Some.Total.Seconds(), "resource", p.name, "kind", "some")
+		if p.HasFull {
+			m.sample("pressure_stalled_seconds_total", p.Full.Total.Seconds(), "resource", p.name, "kind", "full")
+		}
+	}
+
+	m.gauge("processes", "Number of processes.", float64(len(s.Processes)))
+
+	_, err := io.WriteString(w, m.b.String())
+	return err
+}
+
+// namedPressure is a PSI resource with its label value.
+type namedPressure struct {
+	name string
+	hundler.PressureResource
+}
+
+// pressureResources returns the PSI resources the kernel reports.
+func pressureResources(p hundler.PressureStat) []namedPressure {
+	var res []namedPressure
+	for _, r := range []namedPressure{{"cpu", p.CPU}, {"memory", p.Memory}, {"io", p.IO}} {
+		if r.Available {
+			res = append(res, r)
+		}
+	}
+	return res
+}
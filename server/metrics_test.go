@@ -0,0 +1,193 @@
+package server
+
+import (
+	"basicsystemmonitor/hundler"
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parsedMetrics maps a series, its name followed by its labels as written, to its value.
+type parsedMetrics struct {
+	types  map[string]string
+	series map[string]float64
+}
+
+var (
+	metaLine   = regexp.MustCompile(`^# (HELP|TYPE) ([a-zA-Z_:][a-zA-Z0-9_:]*) (.*)$`)
+	sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(.*)\})? (\S+)$`)
+	labelPair  = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\]|\\.)*)"(,|$)`)
+)
+
+// parseMetrics parses the Prometheus text exposition format strictly enough to catch
+// malformed output: every sample must follow the HELP and TYPE lines of its family,
+// families and series must be unique and values must be valid floats.
+func parseMetrics(t *testing.T, text string) parsedMetrics {
+	t.Helper()
+	p := parsedMetrics{types: map[string]string{}, series: map[string]float64{}}
+	helped := map[string]bool{}
+	current := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if m := metaLine.FindStringSubmatch(line); m != nil {
+			switch m[1] {
+			case "HELP":
+				if helped[m[2]] {
+					t.Errorf("line %d: duplicate HELP for %s", n, m[2])
+				}
+				helped[m[2]] = true
+			case "TYPE":
+				if _, dup := p.types[m[2]]; dup {
+					t.Errorf("line %d: duplicate TYPE for %s", n, m[2])
+				}
+				if m[3] != "gauge" && m[3] != "counter" {
+					t.Errorf("line %d: unexpected type %q", n, m[3])
+				}
+				if m[3] == "counter" && !strings.HasSuffix(m[2], "_total") {
+					t.Errorf("line %d: counter %s should end in _total", n, m[2])
+				}
+				p.types[m[2]] = m[3]
+				current = m[2]
+			}
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("line %d: malformed sample %q", n, line)
+			continue
+		}
+		if m[1] != current || !helped[current] {
+			t.Errorf("line %d: sample %s outside of its family (current %s)", n, m[1], current)
+		}
+		for rest := m[3]; rest != ""; {
+			l := labelPair.FindStringSubmatch(rest)
+			if l == nil {
+				t.Errorf("line %d: malformed labels %q", n, m[3])
+				break
+			}
+			rest = rest[len(l[0]):]
+		}
+		v, err := strconv.ParseFloat(m[4], 64)
+		if err != nil {
+			t.Errorf("line %d: bad value %q", n, m[4])
+		}
+		key := m[1] + m[2]
+		if _, dup := p.series[key]; dup {
+			t.Errorf("line %d: duplicate series %s", n, key)
+		}
+		p.series[key] = v
+	}
+	return p
+}
+
+func (p parsedMetrics) value(t *testing.T, series string) float64 {
+	t.Helper()
+	v, ok := p.series[series]
+	if !ok {
+		t.Errorf("missing series %s", series)
+	}
+	return v
+}
+
+func metricsSnapshot() hundler.Snapshot {
+	return hundler.Snapshot{
+		Time: time.Unix(1_700_000_000, 0),
+		Cpu: hundler.CpuStat{
+			Percent: 25,
+			PerCore: []float64{50, 0},
+			Times:   hundler.CpuTimes{User: 20, System: 5, Idle: 75},
+		},
+		Load: hundler.LoadStat{Load1: 1.5, ProcsRunning: 3, BootTime: time.Unix(1_699_000_000, 0)},
+		Ram:  hundler.RamStat{Total: 8 << 30, Used: 2 << 30, Available: 6 << 30, TotalSwapOut: 4096},
+		Disks: []hundler.DiskStat{
+			{Path: "/", Device: "/dev/sda1", Fstype: "ext4", Total: 100, Used: 40, Free: 60, InodesTotal: 10, InodesFree: 7},
+			{Path: `/mnt/odd "name"\dir`, Device: "/dev/sdb1", Fstype: "xfs", Total: 50},
+		},
+		DiskIO:     []hundler.DiskIOStat{{Name: "sda", ReadBytesPerSec: 4096, TotalReadBytes: 1 << 20, TotalWrites: 30, TotalIoTime: 2500 * time.Millisecond}},
+		Interfaces: []hundler.InterfaceStat{{Name: "eth0", TotalBytesSent: 1000, TotalBytesRecv: 2000, PacketsRecvPerSec: 5, TotalPacketsRecv: 20, TotalDropin: 3}},
+		Pressure: hundler.PressureStat{
+			CPU:    hundler.PressureResource{Available: true, Some: hundler.PressureLine{Total: 1500 * time.Millisecond}},
+			Memory: hundler.PressureResource{Available: true, HasFull: true, Full: hundler.PressureLine{Total: time.Second}},
+		},
+		Processes: make([]hundler.ProcessStat, 42),
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, metricsSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+	p := parseMetrics(t, buf.String())
+
+	checks := []struct {
+		series string
+		want   float64
+	}{
+		{"sysmon_cpu_usage_ratio", 0.25},
+		{`sysmon_cpu_core_usage_ratio{cpu="0"}`, 0.5},
+		{`sysmon_cpu_mode_ratio{mode="user"}`, 0.2},
+		{"sysmon_load1", 1.5},
+		{"sysmon_procs_running", 3},
+		{"sysmon_boot_time_seconds", 1_699_000_000},
+		{"sysmon_memory_available_bytes", 6 << 30},
+		{`sysmon_filesystem_used_bytes{mountpoint="/",device="/dev/sda1",fstype="ext4"}`, 40},
+		{`sysmon_filesystem_inodes_free{mountpoint="/",device="/dev/sda1",fstype="ext4"}`, 7},
+		{`sysmon_filesystem_size_bytes{mountpoint="/mnt/odd \"name\"\\dir",device="/dev/sdb1",fstype="xfs"}`, 50},
+		{"sysmon_swap_out_bytes_total", 4096},
+		{`sysmon_disk_read_bytes_total{device="sda"}`, 1 << 20},
+		{`sysmon_disk_writes_completed_total{device="sda"}`, 30},
+		{`sysmon_disk_io_time_seconds_total{device="sda"}`, 2.5},
+		{`sysmon_network_receive_bytes_total{interface="eth0"}`, 2000},
+		{`sysmon_network_receive_packets_total{interface="eth0"}`, 20},
+		{`sysmon_network_receive_drops_total{interface="eth0"}`, 3},
+		{`sysmon_pressure_stalled_seconds_total{resource="cpu",kind="some"}`, 1.5},
+		{`sysmon_pressure_stalled_seconds_total{resource="memory",kind="full"}`, 1},
+		{"sysmon_processes", 42},
+	}
+	for _, c := range checks {
+		if got := p.value(t, c.series); got != c.want {
+			t.Errorf("%s = %v, want %v", c.series, got, c.want)
+		}
+	}
+	if _, ok := p.series[`sysmon_pressure_stalled_seconds_total{resource="io",kind="some"}`]; ok {
+		t.Error("unavailable PSI resources should not be exported")
+	}
+	// Cumulative values are counters, so Prometheus computes rates over its own window.
+	for name, typ := range map[string]string{
+		"sysmon_network_transmit_bytes_total": "counter",
+		"sysmon_network_receive_errors_total": "counter",
+		"sysmon_disk_written_bytes_total":     "counter",
+		"sysmon_disk_read_time_seconds_total": "counter",
+		"sysmon_swap_in_bytes_total":          "counter",
+		"sysmon_memory_used_bytes":            "gauge",
+	} {
+		if p.types[name] != typ {
+			t.Errorf("type of %s = %q, want %q", name, p.types[name], typ)
+		}
+	}
+	for name, typ := range p.types {
+		if strings.HasSuffix(name, "_per_second") || typ == "counter" && !strings.HasSuffix(name, "_total") {
+			t.Errorf("%s (%s) should be a counter ending in _total", name, typ)
+		}
+	}
+}
+
+func TestWriteMetricsEmptySnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, hundler.Snapshot{}); err != nil {
+		t.Fatal(err)
+	}
+	p := parseMetrics(t, buf.String())
+	if _, ok := p.series["sysmon_boot_time_seconds"]; ok {
+		t.Error("an unknown boot time should not be exported")
+	}
+	if got := p.value(t, "sysmon_processes"); got != 0 {
+		t.Errorf("sysmon_processes = %v, want 0", got)
+	}
+}
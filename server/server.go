@@ -0,0 +1,63 @@
+// Package server exposes the latest monitor snapshot over HTTP.
+package server
+
+import (
+	"basicsystemmonitor/hundler"
+	"embed"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Options configures the HTTP server.
+type Options struct {
+	RefreshInterval time.Duration // How often /api/v1/stream sends a snapshot
+	Hostname        string        // Reported in API records; defaults to os.Hostname
+}
+
+// web holds the dashboard, served from the binary so that it works without internet access.
+//
+//go:embed web
+var web embed.FS
+
+type server struct {
+	store *hundler.SnapshotStore
+	opts  Options
+}
+
+// New returns the HTTP handler serving the snapshots of store:
+//
+//	GET /                   the dashboard, a single page following /api/v1/stream
+//	GET /metrics            Prometheus text exposition format
+//	GET /api/v1/snapshot    the latest snapshot as a JSON encoder.Record
+//	GET /api/v1/processes   the latest processes as JSON encoder.Process records, see handleProcesses
+//	GET /api/v1/stream      snapshots as Server-Sent Events every RefreshInterval
+func New(store *hundler.SnapshotStore, opts Options) http.Handler {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Second
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	s := &server{store: store, opts: opts}
+	static, err := fs.Sub(web, "web")
+	if err != nil {
+		panic(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /api/v1/snapshot", s.handleSnapshot)
+	mux.HandleFunc("GET /api/v1/processes", s.handleProcesses)
+	mux.HandleFunc("GET /api/v1/stream", s.handleStream)
+	return mux
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := WriteMetrics(w, s.store.Snapshot()); err != nil {
+		log.Printf("Error writing metrics: %v", err)
+	}
+}
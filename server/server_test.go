@@ -0,0 +1,92 @@
+package server
+
+import (
+	"basicsystemmonitor/hundler"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestStore returns a store holding the given snapshot's CPU and process values.
+func newTestStore(t *testing.T, s hundler.Snapshot) *hundler.SnapshotStore {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	cpuCh := make(chan hundler.CpuStat)
+	procCh := make(chan []hundler.ProcessStat)
+	store := hundler.WatchMonitors(ctx, hundler.Monitors{Cpu: cpuCh, Processes: procCh})
+	// Sending twice guarantees the first value has been stored.
+	for i := 0; i < 2; i++ {
+		cpuCh <- s.Cpu
+		procCh <- s.Processes
+	}
+	return store
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	srv := httptest.NewServer(New(newTestStore(t, metricsSnapshot()), Options{}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	p := parseMetrics(t, string(body))
+	if got := p.value(t, "sysmon_cpu_usage_ratio"); got != 0.25 {
+		t.Errorf("sysmon_cpu_usage_ratio = %v, want 0.25", got)
+	}
+	if got := p.value(t, "sysmon_processes"); got != 42 {
+		t.Errorf("sysmon_processes = %v, want 42", got)
+	}
+
+	post, err := http.Post(srv.URL+"/metrics", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /metrics = %s, want 405", post.Status)
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	srv := httptest.NewServer(New(newTestStore(t, metricsSnapshot()), Options{}))
+	defer srv.Close()
+
+	for path, contentType := range map[string]string{
+		"/":          "text/html",
+		"/app.js":    "text/javascript",
+		"/style.css": "text/css",
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), contentType) {
+			t.Errorf("GET %s: status %d, content type %q", path, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		// The dashboard must work on hosts without internet access.
+		if strings.Contains(string(body), "http://") || strings.Contains(string(body), "https://") {
+			t.Errorf("GET %s refers to external assets", path)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/missing.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /missing.js = %s, want 404", resp.Status)
+	}
+}
@@ -0,0 +1,284 @@
+// Live dashboard fed by the /api/v1/stream Server-Sent Events.
+"use strict";
+
+// historyLength is the number of samples kept for the charts.
+const historyLength = 120;
+// maxRows is the number of processes shown in the table.
+const maxRows = 200;
+
+const history = {
+  cpu: [],
+  ram: [],
+  sent: [],
+  recv: [],
+  // Read plus write throughput by device name. Partitions and device-mapper volumes
+  // are counted again on top of their disks, so devices are never added up.
+  io: new Map(),
+};
+
+// devicePalette are the colours the disk I/O series take in turn.
+const devicePalette = ["--send", "--recv", "--ram", "--cpu", "--text", "--muted"];
+let nextDeviceColor = 0;
+
+let latest = null;
+let sortKey = "cpu_percent";
+let sortDesc = true;
+
+// byteCountSI formats a byte count with SI units, like the terminal UI.
+function byteCountSI(b) {
+  const unit = 1000;
+  if (b < unit) {
+    return Math.round(b) + " B";
+  }
+  let div = unit;
+  let exp = 0;
+  for (let n = b / unit; n >= unit; n /= unit) {
+    div *= unit;
+    exp++;
+  }
+  return (b / div).toFixed(1) + " " + "kMGTPE"[exp] + "B";
+}
+
+function formatDuration(seconds) {
+  let s = Math.floor(seconds);
+  const days = Math.floor(s / 86400);
+  s %= 86400;
+  const hours = Math.floor(s / 3600);
+  const minutes = Math.floor((s % 3600) / 60);
+  return (days > 0 ? days + "d " : "") + hours + "h " + minutes + "m";
+}
+
+function push(samples, v) {
+  if (samples.length === historyLength) {
+    samples.shift();
+  }
+  samples.push(v);
+}
+
+// drawChart draws one line per series. max is the top of the scale, or null to fit the data.
+function drawChart(canvas, series, max) {
+  const ratio = window.devicePixelRatio || 1;
+  const width = canvas.clientWidth;
+  const height = canvas.clientHeight;
+  if (canvas.width !== width * ratio || canvas.height !== height * ratio) {
+    canvas.width = width * ratio;
+    canvas.height = height * ratio;
+  }
+  const ctx = canvas.getContext("2d");
+  ctx.setTransform(ratio, 0, 0, ratio, 0, 0);
+  ctx.clearRect(0, 0, width, height);
+
+  const style = getComputedStyle(document.documentElement);
+  if (max === null) {
+    max = 1;
+    for (const s of series) {
+      max = Math.max(max, ...s.values);
+    }
+  }
+
+  ctx.strokeStyle = style.getPropertyValue("--grid");
+  ctx.lineWidth = 1;
+  for (let i = 1; i < 4; i++) {
+    const y = Math.round((height * i) / 4) + 0.5;
+    ctx.beginPath();
+    ctx.moveTo(0, y);
+    ctx.lineTo(width, y);
+    ctx.stroke();
+  }
+
+  const step = width / (historyLength - 1);
+  for (const s of series) {
+    // The newest sample is at the right edge.
+    const offset = historyLength - s.values.length;
+    ctx.strokeStyle = style.getPropertyValue(s.color);
+    ctx.lineWidth = 1.5;
+    ctx.beginPath();
+    s.values.forEach((v, i) => {
+      const x = (offset + i) * step;
+      const y = height - (v / max) * (height - 2) - 1;
+      if (i === 0) {
+        ctx.moveTo(x, y);
+      } else {
+        ctx.lineTo(x, y);
+      }
+    });
+    ctx.stroke();
+  }
+}
+
+// cell appends a table cell. Text is never parsed as HTML since process names and
+// command lines are chosen by whoever started the process.
+function cell(row, text, className) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  if (className) {
+    td.className = className;
+  }
+  row.appendChild(td);
+  return td;
+}
+
+function usageBar(percent) {
+  const bar = document.createElement("div");
+  bar.className = "bar" + (percent >= 90 ? " full" : percent >= 75 ? " warn" : "");
+  bar.title = percent.toFixed(1) + "%";
+  const fill = document.createElement("div");
+  fill.style.width = Math.min(percent, 100) + "%";
+  bar.appendChild(fill);
+  return bar;
+}
+
+function renderDisks(disks) {
+  const body = document.querySelector("#disks tbody");
+  const rows = disks.map((d) => {
+    const row = document.createElement("tr");
+    cell(row, d.path);
+    cell(row, d.device);
+    cell(row, d.fstype);
+    cell(row, byteCountSI(d.total), "num");
+    cell(row, byteCountSI(d.used), "num");
+    cell(row, "").appendChild(usageBar(d.used_percent));
+    return row;
+  });
+  body.replaceChildren(...rows);
+}
+
+function renderProcesses() {
+  if (!latest) {
+    return;
+  }
+  const needle = document.getElementById("filter").value.trim().toLowerCase();
+  let procs = latest.processes;
+  if (needle !== "") {
+    procs = procs.filter((p) =>
+      [p.name, p.user, p.cmdline].some((s) => s.toLowerCase().includes(needle)),
+    );
+  }
+  procs = procs.slice().sort((a, b) => {
+    const x = a[sortKey];
+    const y = b[sortKey];
+    const order = typeof x === "string" ? x.localeCompare(y) : x - y;
+    return (sortDesc ? -order : order) || a.pid - b.pid;
+  });
+
+  const total = latest.processes.length;
+  document.getElementById("process-count").textContent =
+    procs.length === total ? "(" + total + ")" : "(" + procs.length + " of " + total + ")";
+
+  const rows = procs.slice(0, maxRows).map((p) => {
+    const row = document.createElement("tr");
+    cell(row, p.pid, "num");
+    cell(row, p.user);
+    cell(row, p.name);
+    cell(row, p.cpu_percent.toFixed(1), "num");
+    cell(row, byteCountSI(p.memory_bytes), "num");
+    cell(row, byteCountSI(p.read_bytes_per_sec), "num");
+    cell(row, byteCountSI(p.write_bytes_per_sec), "num");
+    cell(row, p.fds, "num");
+    cell(row, p.cmdline, "cmd").title = p.cmdline;
+    return row;
+  });
+  document.querySelector("#processes tbody").replaceChildren(...rows);
+
+  for (const th of document.querySelectorAll("#processes th[data-key]")) {
+    th.classList.toggle("sorted", th.dataset.key === sortKey);
+    th.classList.toggle("asc", th.dataset.key === sortKey && !sortDesc);
+  }
+}
+
+function drawCharts() {
+  drawChart(document.getElementById("cpu-chart"), [{ values: history.cpu, color: "--cpu" }], 100);
+  drawChart(document.getElementById("ram-chart"), [{ values: history.ram, color: "--ram" }], 100);
+  drawChart(
+    document.getElementById("net-chart"),
+    [
+      { values: history.sent, color: "--send" },
+      { values: history.recv, color: "--recv" },
+    ],
+    null,
+  );
+  drawChart(document.getElementById("io-chart"), [...history.io.values()], null);
+}
+
+// updateDiskIO adds a sample to the series of every device in diskIO, drops the series
+// of devices that went away and lists the devices, in their colours, in the caption.
+function updateDiskIO(diskIO) {
+  const seen = new Set();
+  const legend = [];
+  const style = getComputedStyle(document.documentElement);
+  for (const d of diskIO) {
+    let series = history.io.get(d.name);
+    if (!series) {
+      series = { values: [], color: devicePalette[nextDeviceColor++ % devicePalette.length] };
+      history.io.set(d.name, series);
+    }
+    const total = d.read_bytes_per_sec + d.write_bytes_per_sec;
+    push(series.values, total);
+    seen.add(d.name);
+
+    const span = document.createElement("span");
+    span.style.color = style.getPropertyValue(series.color);
+    span.textContent = d.name + " " + byteCountSI(total) + "/s";
+    legend.push(span);
+  }
+  for (const name of history.io.keys()) {
+    if (!seen.has(name)) {
+      history.io.delete(name);
+    }
+  }
+  document.getElementById("io-value").replaceChildren(...legend);
+}
+
+// update takes in one record of the stream, in the schema of the JSON output.
+function update(s) {
+  latest = s;
+  push(history.cpu, s.cpu.percent);
+  push(history.ram, s.memory.used_percent);
+  push(history.sent, s.network.bytes_sent_per_sec);
+  push(history.recv, s.network.bytes_recv_per_sec);
+  updateDiskIO(s.disk_io);
+
+  document.getElementById("hostname").textContent = s.hostname;
+  document.getElementById("cpu-value").textContent =
+    s.cpu.percent.toFixed(1) + "% of " + s.cpu.per_core.length + " cores";
+  document.getElementById("ram-value").textContent =
+    byteCountSI(s.memory.used) + " of " + byteCountSI(s.memory.total) + " (" + s.memory.used_percent.toFixed(1) + "%)";
+  document.getElementById("net-value").textContent =
+    "↑ " + byteCountSI(s.network.bytes_sent_per_sec) + "/s  ↓ " + byteCountSI(s.network.bytes_recv_per_sec) + "/s";
+  document.getElementById("summary").textContent =
+    "load " + [s.load.load1, s.load.load5, s.load.load15].map((l) => l.toFixed(2)).join(" ") +
+    "   up " + formatDuration(s.load.uptime_seconds) +
+    "   " + new Date(s.timestamp).toLocaleTimeString();
+
+  drawCharts();
+  renderDisks(s.disks);
+  renderProcesses();
+}
+
+for (const th of document.querySelectorAll("#processes th[data-key]")) {
+  th.addEventListener("click", () => {
+    if (sortKey === th.dataset.key) {
+      sortDesc = !sortDesc;
+    } else {
+      sortKey = th.dataset.key;
+      // Numbers read best busiest first, text alphabetically.
+      sortDesc = th.classList.contains("num");
+    }
+    renderProcesses();
+  });
+}
+document.getElementById("filter").addEventListener("input", renderProcesses);
+window.addEventListener("resize", drawCharts);
+
+const status = document.getElementById("status");
+const events = new EventSource("api/v1/stream");
+events.addEventListener("snapshot", (e) => {
+  status.textContent = "live";
+  status.className = "status live";
+  update(JSON.parse(e.data));
+});
+events.addEventListener("error", () => {
+  // EventSource reconnects by itself.
+  status.textContent = "reconnecting…";
+  status.className = "status lost";
+});
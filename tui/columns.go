@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// processColumn describes one selectable column of the process table.
+type processColumn struct {
+	header string
+	width  int
+	field  hundler.ProcessField // Fields the column shows; rendered as "-" when unavailable
+	value  func(p hundler.ProcessStat) string
+}
+
+// processColumns holds every column that can be shown in the process table, by config name.
+var processColumns = map[string]processColumn{
+	"pid":     {"PID", 8, 0, func(p hundler.ProcessStat) string { return fmt.Sprintf("%d", p.Pid) }},
+	"ppid":    {"PPID", 8, hundler.FieldPPid, func(p hundler.ProcessStat) string { return fmt.Sprintf("%d", p.PPid) }},
+	"user":    {"USER", 10, hundler.FieldUser, func(p hundler.ProcessStat) string { return p.Username }},
+	"uid":     {"UID", 6, hundler.FieldUser, func(p hundler.ProcessStat) string { return fmt.Sprintf("%d", p.UID) }},
+	"state":   {"S", 1, hundler.FieldState, func(p hundler.ProcessStat) string { return p.State }},
+	"threads": {"THR", 4, hundler.FieldThreads, func(p hundler.ProcessStat) string { return fmt.Sprintf("%d", p.NumThreads) }},
+	"nice":    {"NI", 3, hundler.FieldNice, func(p hundler.ProcessStat) string { return fmt.Sprintf("%d", p.Nice) }},
+	"name":    {"NAME", 30, 0, func(p hundler.ProcessStat) string { return p.Name }},
+	"cmdline": {"COMMAND", 50, hundler.FieldCmdline, func(p hundler.ProcessStat) string { return p.Cmdline }},
+	"cgroup":  {"CGROUP", 40, hundler.FieldCgroup, func(p hundler.ProcessStat) string { return p.Cgroup }},
+	"start":   {"START", 8, 0, func(p hundler.ProcessStat) string { return formatStart(p.CreateTime, time.Now()) }},
+	"cpu":     {"CPU%", 8, hundler.FieldCPU, func(p hundler.ProcessStat) string { return fmt.Sprintf("%.2f%%", p.CPUPercent) }},
+	"mem":     {"MEM", 9, hundler.FieldMemory, func(p hundler.ProcessStat) string { return ByteCountSI(p.MemoryBytes) }},
+	"vms":     {"VIRT", 9, hundler.FieldMemory, func(p hundler.ProcessStat) string { return ByteCountSI(p.VMS) }},
+	"mem%":    {"MEM%", 6, hundler.FieldMemory, func(p hundler.ProcessStat) string { return fmt.Sprintf("%.1f%%", p.MemoryPercent) }},
+	"read":    {"IO READ", 9, hundler.FieldIO, func(p hundler.ProcessStat) string { return ByteCountSI(uint64(p.ReadBytesPerSec)) + "/s" }},
+	"write":   {"IO WRITE", 9, hundler.FieldIO, func(p hundler.ProcessStat) string { return ByteCountSI(uint64(p.WriteBytesPerSec)) + "/s" }},
+	"fds":     {"FDS", 5, hundler.FieldFDs, func(p hundler.ProcessStat) string { return fmt.Sprintf("%d", p.NumFDs) }},
+}
+
+// formatStart formats a process start time the way ps does: the time of day for
+// processes started in the last 24 hours, then the date, then just the year.
+func formatStart(t, now time.Time) string {
+	switch age := now.Sub(t); {
+	case age < 24*time.Hour:
+		return t.Format("15:04:05")
+	case age < 365*24*time.Hour:
+		return t.Format("Jan02")
+	default:
+		return t.Format("2006")
+	}
+}
+
+// DefaultProcessColumns are the process table columns shown when none are configured.
+var DefaultProcessColumns = []string{"pid", "name", "cpu", "mem"}
+
+// ValidateProcessColumns returns an error naming the first unknown column, if any.
+func ValidateProcessColumns(names []string) error {
+	for _, name := range names {
+		if _, ok := processColumns[name]; !ok {
+			return fmt.Errorf("unknown process column %q", name)
+		}
+	}
+	return nil
+}
+
+// truncate shortens s to at most width runes, marking the cut with '…'.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// processHeader renders the header line of the process table for the given columns.
+func processHeader(names []string) string {
+	cells := make([]string, len(names))
+	for i, name := range names {
+		c := processColumns[name]
+		cells[i] = fmt.Sprintf("%-*s", c.width, c.header)
+	}
+	return strings.TrimRight(strings.Join(cells, " "), " ")
+}
+
+// processRow renders one process as a line of the process table.
+func processRow(names []string, p hundler.ProcessStat) string {
+	cells := make([]string, len(names))
+	for i, name := range names {
+		c := processColumns[name]
+		value := "-"
+		if p.Available(c.field) {
+			value = c.value(p)
+		}
+		cells[i] = fmt.Sprintf("%-*s", c.width, truncate(value, c.width))
+	}
+	return strings.TrimRight(strings.Join(cells, " "), " ")
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inputPrompt is a single-line text prompt that applies an action to the selected process.
+type inputPrompt struct {
+	label string
+	input string
+	apply func(input string) (status string, err error)
+}
+
+// openNicePrompt asks for a new nice value for the selected process.
+func (m *MainModel) openNicePrompt() {
+	p, ok := m.selectedProcess()
+	if !ok {
+		return
+	}
+	m.input = &inputPrompt{
+		label: fmt.Sprintf("Nice value for pid %d (%s), -20 to 19", p.Pid, p.Name),
+		input: strconv.Itoa(int(p.Nice)),
+		apply: func(input string) (string, error) {
+			nice, err := strconv.Atoi(input)
+			if err != nil || nice < -20 || nice > 19 {
+				return "", fmt.Errorf("invalid nice value %q", input)
+			}
+			if err := m.control.Renice(p.Pid, nice); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Set nice of pid %d to %d", p.Pid, nice), nil
+		},
+	}
+}
+
+// openIOPriorityPrompt asks for a new I/O class and level for the selected process.
+func (m *MainModel) openIOPriorityPrompt() {
+	p, ok := m.selectedProcess()
+	if !ok {
+		return
+	}
+	m.input = &inputPrompt{
+		label: fmt.Sprintf("I/O priority for pid %d (%s), e.g. idle, best-effort:7, realtime:0", p.Pid, p.Name),
+		apply: func(input string) (string, error) {
+			class, level, err := hundler.ParseIOPriority(input)
+			if err != nil {
+				return "", err
+			}
+			if err := m.control.SetIOPriority(p.Pid, class, level); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Set I/O priority of pid %d to %s", p.Pid, input), nil
+		},
+	}
+}
+
+// openAffinityPrompt asks for the CPUs the selected process may run on.
+func (m *MainModel) openAffinityPrompt() {
+	p, ok := m.selectedProcess()
+	if !ok {
+		return
+	}
+	m.input = &inputPrompt{
+		label: fmt.Sprintf("CPU affinity for pid %d (%s), e.g. 0-3,6", p.Pid, p.Name),
+		apply: func(input string) (string, error) {
+			cpus, err := hundler.ParseCPUList(input)
+			if err != nil {
+				return "", err
+			}
+			if err := m.control.SetAffinity(p.Pid, cpus); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Set CPU affinity of pid %d to %s", p.Pid, input), nil
+		},
+	}
+}
+
+// selectedProcess returns the process under the cursor, if a process is selected.
+func (m *MainModel) selectedProcess() (hundler.ProcessStat, bool) {
+	if m.showingGroups() || len(m.Processes) == 0 {
+		return hundler.ProcessStat{}, false
+	}
+	return m.Processes[m.cursor], true
+}
+
+// updateInputPrompt handles key presses while an input prompt is open.
+func (m MainModel) updateInputPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.input = nil
+		m.status = "Cancelled"
+	case tea.KeyEnter:
+		status, err := m.input.apply(m.input.input)
+		if err != nil {
+			m.status = actionError(err)
+		} else {
+			m.status = status
+		}
+		m.input = nil
+	case tea.KeyBackspace:
+		if r := []rune(m.input.input); len(r) > 0 {
+			m.input.input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.input.input += " "
+	case tea.KeyRunes:
+		m.input.input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// actionError explains a failed process action, pointing out missing privileges.
+func actionError(err error) string {
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		return fmt.Sprintf("Error: %v (raising priority or changing other users' processes needs root or CAP_SYS_NICE)", err)
+	case errors.Is(err, errors.ErrUnsupported):
+		return "Error: not supported on this platform"
+	default:
+		return fmt.Sprintf("Error: %v", err)
+	}
+}
+
+// render renders the prompt with its current input.
+func (p *inputPrompt) render() string {
+	return fmt.Sprintf("%s (enter to apply, esc to cancel): %s█\n", p.label, p.input)
+}
@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyLength is the number of samples kept per process for the detail sparklines.
+const historyLength = 120
+
+// processHistory is the CPU and RSS history of one process since the monitor started.
+type processHistory struct {
+	createTime time.Time // Tells a reused pid apart from the process the history belongs to
+	cpu        []float64
+	rss        []float64
+}
+
+// recordHistory appends the latest sample of every process to its history and
+// forgets processes that exited.
+func (m *MainModel) recordHistory() {
+	alive := make(map[int32]bool, len(m.AllProcesses))
+	for _, p := range m.AllProcesses {
+		alive[p.Pid] = true
+		h, ok := m.history[p.Pid]
+		if !ok || !h.createTime.Equal(p.CreateTime) {
+			h = &processHistory{createTime: p.CreateTime}
+			m.history[p.Pid] = h
+		}
+		h.cpu = appendSample(h.cpu, p.CPUPercent)
+		h.rss = appendSample(h.rss, float64(p.MemoryBytes))
+	}
+	for pid := range m.history {
+		if !alive[pid] {
+			delete(m.history, pid)
+		}
+	}
+}
+
+// appendSample appends v, dropping the oldest sample once historyLength is reached.
+func appendSample(samples []float64, v float64) []float64 {
+	if len(samples) == historyLength {
+		samples = append(samples[:0], samples[1:]...)
+	}
+	return append(samples, v)
+}
+
+// detailPane is the state of the detail view of one process opened with enter.
+type detailPane struct {
+	pid     int32
+	name    string
+	ch      <-chan hundler.ProcessDetail
+	cancel  context.CancelFunc // Stops the detail monitor
+	detail  *hundler.ProcessDetail
+	exited  bool
+	showEnv bool
+	scroll  int // Index of the first visible line
+}
+
+// detailMsg carries a refresh of the open detail pane.
+type detailMsg struct {
+	ch     <-chan hundler.ProcessDetail
+	detail hundler.ProcessDetail
+}
+
+// detailDoneMsg reports that a detail monitor stopped, because the pane was closed or
+// the process exited.
+type detailDoneMsg struct {
+	ch <-chan hundler.ProcessDetail
+}
+
+// waitForDetail waits for the next refresh of a detail pane.
+func waitForDetail(ch <-chan hundler.ProcessDetail) tea.Cmd {
+	return func() tea.Msg {
+		d, ok := <-ch
+		if !ok {
+			return detailDoneMsg{ch: ch}
+		}
+		return detailMsg{ch: ch, detail: d}
+	}
+}
+
+// openDetail opens the detail pane of the selected process and starts collecting its
+// details. They are only gathered while the pane is open.
+func (m *MainModel) openDetail() tea.Cmd {
+	p, ok := m.selectedProcess()
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := m.opts.DetailMonitor(ctx, p.Pid)
+	m.detail = &detailPane{pid: p.Pid, name: p.Name, ch: ch, cancel: cancel}
+	return waitForDetail(ch)
+}
+
+// closeDetail closes the detail pane and stops its monitor.
+func (m *MainModel) closeDetail() {
+	m.detail.cancel()
+	m.detail = nil
+}
+
+// updateDetail handles key presses while the detail pane is open.
+func (m MainModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.detail
+	page := m.detailHeight()
+	switch msg.String() {
+	case "q", "ctrl+c":
+		m.closeDetail()
+		return m, tea.Quit
+	case "esc", "enter", "backspace":
+		m.closeDetail()
+	case "e": // Toggle the environment
+		d.showEnv = !d.showEnv
+	case "up", "k":
+		d.scroll--
+	case "down", "j":
+		d.scroll++
+	case "pgup":
+		d.scroll -= page
+	case "pgdown":
+		d.scroll += page
+	case "home", "g":
+		d.scroll = 0
+	case "end", "G":
+		d.scroll = len(d.lines(m.history[d.pid]))
+	}
+	d.scroll = max(min(d.scroll, len(d.lines(m.history[d.pid]))-page), 0)
+	return m, nil
+}
+
+// detailHeight returns how many lines of the detail pane fit in place of the process table.
+func (m MainModel) detailHeight() int {
+	return detailHeight(m.processListHeight())
+}
+
+// detailHeight returns the height of the detail pane shown in place of rows process rows.
+func detailHeight(rows int) int {
+	// The table's title and header make room for the pane's title, plus one more line.
+	return rows + 1
+}
+
+// render renders height lines of the detail pane.
+func (d *detailPane) render(history *processHistory, height int) string {
+	lines := d.lines(history)
+	end := min(d.scroll+height, len(lines))
+	title := fmt.Sprintf("Process %d (%s)   'e' environment, ↑/↓ to scroll, esc to close", d.pid, d.name)
+	if len(lines) > height {
+		title += fmt.Sprintf("   [%d-%d of %d]", d.scroll+1, end, len(lines))
+	}
+	return title + "\n" + strings.Join(lines[min(d.scroll, end):end], "\n") + "\n"
+}
+
+// lines renders the whole detail pane, one entry per line.
+func (d *detailPane) lines(history *processHistory) []string {
+	var lines []string
+	add := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	section := func(name string) {
+		lines = append(lines, "", name+":")
+	}
+
+	if history != nil && len(history.cpu) > 0 {
+		last := len(history.cpu) - 1
+		add("CPU  %s %.2f%%", Sparkline(history.cpu, 60), history.cpu[last])
+		add("RSS  %s %s", Sparkline(history.rss, 60), ByteCountSI(uint64(history.rss[last])))
+	}
+	switch {
+	case d.exited:
+		add("The process has exited.")
+		return lines
+	case d.detail == nil:
+		add("Loading…")
+		return lines
+	}
+
+	p := d.detail
+	field := func(label string, s hundler.DetailSection, value string) {
+		if !p.Available(s) {
+			value = "-"
+		}
+		add("%-9s %s", label+":", value)
+	}
+	field("Command", hundler.SectionCmdline, p.Cmdline)
+	field("Exe", hundler.SectionExe, p.Exe)
+	field("Cwd", hundler.SectionCwd, p.Cwd)
+	field("Cgroup", hundler.SectionCgroup, p.Cgroup)
+
+	section("Memory maps")
+	if p.Available(hundler.SectionMemory) {
+		mm := p.Memory
+		add("  %d mappings   size %s   rss %s   pss %s   swap %s", mm.Mappings, ByteCountSI(mm.Size), ByteCountSI(mm.Rss), ByteCountSI(mm.Pss), ByteCountSI(mm.Swap))
+		for _, top := range mm.Top {
+			path := top.Path
+			if path == "" {
+				path = "[anon]"
+			}
+			add("  %9s rss %9s size  %s", ByteCountSI(top.Rss), ByteCountSI(top.Size), path)
+		}
+	} else {
+		add("  -")
+	}
+
+	section(fmt.Sprintf("Threads (%d)", len(p.Threads)))
+	if p.Available(hundler.SectionThreads) {
+		add("  %-8s %-16s %s", "TID", "NAME", "CPU%")
+		for _, t := range p.Threads {
+			add("  %-8d %-16s %.2f%%", t.Tid, truncate(t.Name, 16), t.CPUPercent)
+		}
+	} else {
+		add("  -")
+	}
+
+	section("Limits")
+	if p.Available(hundler.SectionLimits) {
+		add("  %-11s %-12s %-12s %s", "RESOURCE", "SOFT", "HARD", "USED")
+		for _, l := range p.Limits {
+			used := ""
+			if l.Used > 0 {
+				used = fmt.Sprintf("%d", l.Used)
+			}
+			add("  %-11s %-12s %-12s %s", l.Name, formatLimit(l.Soft), formatLimit(l.Hard), used)
+		}
+	} else {
+		add("  -")
+	}
+
+	section(fmt.Sprintf("Sockets (%d)", len(p.Sockets)))
+	if p.Available(hundler.SectionSockets) {
+		for _, s := range p.Sockets {
+			add("  fd %-5d %-5s %-22s %-22s %s", s.Fd, s.Type, s.Local, s.Remote, s.Status)
+		}
+	} else {
+		add("  -")
+	}
+
+	section(fmt.Sprintf("Open files (%d)", len(p.OpenFiles)))
+	if p.Available(hundler.SectionFiles) {
+		for _, f := range p.OpenFiles {
+			add("  fd %-5d %s", f.Fd, f.Path)
+		}
+	} else {
+		add("  -")
+	}
+
+	section(fmt.Sprintf("Environment (%d)", len(p.Environ)))
+	switch {
+	case !p.Available(hundler.SectionEnviron):
+		add("  -")
+	case !d.showEnv:
+		add("  hidden, press 'e' to show")
+	default:
+		for _, e := range p.Environ {
+			add("  %s", e)
+		}
+	}
+	return lines
+}
+
+// formatLimit formats a resource limit, which may be unlimited.
+func formatLimit(v uint64) string {
+	if v == hundler.RlimitInfinity {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", v)
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeDetailMonitor serves details from a channel the test feeds, recording which pid was
+// opened and closing the channel once the pane's context is cancelled.
+type fakeDetailMonitor struct {
+	pid     int32
+	ch      chan hundler.ProcessDetail
+	stopped chan struct{}
+}
+
+func (f *fakeDetailMonitor) start(ctx context.Context, pid int32) <-chan hundler.ProcessDetail {
+	f.pid = pid
+	f.ch = make(chan hundler.ProcessDetail, 1)
+	f.stopped = make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(f.stopped)
+	}()
+	return f.ch
+}
+
+func TestDetailPane(t *testing.T) {
+	monitor := &fakeDetailMonitor{}
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true, DetailMonitor: monitor.start})
+	for i := 0; i < 3; i++ {
+		next, _ := m.Update(processMsg(treeFixture()))
+		m = next.(MainModel)
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(MainModel)
+	if m.detail == nil || monitor.pid != 12 || cmd == nil {
+		t.Fatalf("enter should open the detail pane of the selected pid 12, opened %d", monitor.pid)
+	}
+	if view := m.View(); !strings.Contains(view, "Loading") || !strings.Contains(view, "CPU  ███ 50.00%") {
+		t.Errorf("pane should show the CPU history while loading:\n%s", view)
+	}
+
+	monitor.ch <- hundler.ProcessDetail{
+		Pid:         12,
+		Cmdline:     "node server.js",
+		Environ:     []string{"SECRET=hunter2"},
+		Threads:     []hundler.ThreadStat{{Tid: 12, Name: "node", CPUPercent: 45}},
+		Limits:      []hundler.ProcessLimit{{Name: "nofile", Soft: 1024, Hard: hundler.RlimitInfinity}},
+		Unavailable: hundler.SectionCwd,
+	}
+	next, _ = m.Update(cmd())
+	m = next.(MainModel)
+	m.height = 200
+	view := m.View()
+	for _, want := range []string{"node server.js", "Cwd:      -", "45.00%", "unlimited", "hidden, press 'e'"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "hunter2") {
+		t.Error("environment should be hidden by default")
+	}
+	if m = press(m, "e"); !strings.Contains(m.View(), "SECRET=hunter2") {
+		t.Error("'e' should show the environment")
+	}
+
+	m = press(m, "esc")
+	if m.detail != nil {
+		t.Error("esc should close the detail pane")
+	}
+	<-monitor.stopped
+}
+
+func TestDetailPaneProcessExited(t *testing.T) {
+	monitor := &fakeDetailMonitor{}
+	m := newTestModel(nil)
+	m.opts.DetailMonitor = monitor.start
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(MainModel)
+	close(monitor.ch)
+	next, _ = m.Update(cmd())
+	m = next.(MainModel)
+	if !strings.Contains(m.View(), "The process has exited.") {
+		t.Error("pane should report that the process exited")
+	}
+}
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"basicsystemmonitor/hundler"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // byteCountSI formats bytes in SI units (kB=1000)
@@ -17,3 +20,278 @@ func ByteCountSI(b uint64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "kMGTPE"[exp])
 }
+
+// Bar renders a fixed-width usage bar for a percentage in the range 0-100.
+func Bar(percent float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	filled := int(percent/100*float64(width) + 0.5)
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > width {
+		filled = width
+	}
+	return "[" + strings.Repeat("|", filled) + strings.Repeat(" ", width-filled) + "]"
+}
+
+// sparkLevels are the glyphs of a sparkline, from lowest to highest.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the last width values as a one-line chart scaled to their maximum.
+func Sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 {
+			level = int(v / peak * float64(len(sparkLevels)-1))
+		}
+		b.WriteRune(sparkLevels[max(level, 0)])
+	}
+	return b.String()
+}
+
+// coreGridColumns picks how many cores to show per row so the grid stays compact
+// on small machines and doesn't grow too tall on large ones.
+func coreGridColumns(cores int) int {
+	switch {
+	case cores <= 4:
+		return cores
+	case cores <= 16:
+		return 4
+	case cores <= 64:
+		return 8
+	default:
+		return 12
+	}
+}
+
+// CoreGrid renders per-core usage as a grid of labelled bars.
+func CoreGrid(perCore []float64) string {
+	if len(perCore) == 0 {
+		return ""
+	}
+	cols := coreGridColumns(len(perCore))
+	barWidth := 10
+	if cols > 4 {
+		barWidth = 5
+	}
+
+	var b strings.Builder
+	for i, p := range perCore {
+		fmt.Fprintf(&b, "%3d %s%5.1f%%", i, Bar(p, barWidth), p)
+		if (i+1)%cols == 0 || i == len(perCore)-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString("  ")
+		}
+	}
+	return b.String()
+}
+
+// cpuTimeSegment is one category in the stacked CPU time bar.
+type cpuTimeSegment struct {
+	label   string
+	glyph   byte
+	percent float64
+}
+
+func cpuTimeSegments(t hundler.CpuTimes) []cpuTimeSegment {
+	return []cpuTimeSegment{
+		{"usr", 'u', t.User},
+		{"nice", 'n', t.Nice},
+		{"sys", 's', t.System},
+		{"iowait", 'w', t.Iowait},
+		{"irq", 'i', t.Irq},
+		{"soft", 'q', t.Softirq},
+		{"steal", 't', t.Steal},
+		{"guest", 'g', t.Guest + t.GuestNice},
+	}
+}
+
+// StackedCpuBar renders CPU time categories as a single bar where each category
+// fills a run of cells with its own glyph.
+func StackedCpuBar(t hundler.CpuTimes, width int) string {
+	segments := cpuTimeSegments(t)
+	// Guest time is already counted in user/nice on Linux, so don't draw it twice.
+	drawn := segments[:len(segments)-1]
+
+	var bar strings.Builder
+	used := 0
+	for _, seg := range drawn {
+		n := int(seg.percent/100*float64(width) + 0.5)
+		if used+n > width {
+			n = width - used
+		}
+		bar.WriteString(strings.Repeat(string(seg.glyph), n))
+		used += n
+	}
+	bar.WriteString(strings.Repeat(" ", width-used))
+	return "[" + bar.String() + "]"
+}
+
+// CpuTimesLegend lists the glyph and percentage of every CPU time category.
+func CpuTimesLegend(t hundler.CpuTimes) string {
+	segments := cpuTimeSegments(t)
+	var legend strings.Builder
+	for i, seg := range segments {
+		if i > 0 {
+			legend.WriteString(" ")
+		}
+		fmt.Fprintf(&legend, "%c:%s %.1f%%", seg.glyph, seg.label, seg.percent)
+	}
+	return legend.String()
+}
+
+// FormatUptime formats a duration as days, hours and minutes (e.g. "3d 4h 12m").
+func FormatUptime(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// LoadLine renders the load averages normalized by core count, the run-queue and the uptime.
+// A normalized load above 1.00 means more runnable work than there are cores to run it.
+// The normalized figures are left out while the core count is unknown.
+func LoadLine(l hundler.LoadStat) string {
+	perCore := ""
+	if l.Cores > 0 {
+		n := float64(l.Cores)
+		perCore = fmt.Sprintf(" (%.2f %.2f %.2f per core)", l.Load1/n, l.Load5/n, l.Load15/n)
+	}
+	return fmt.Sprintf("Load: %.2f %.2f %.2f%s   Tasks: %d running, %d blocked   Up: %s",
+		l.Load1, l.Load5, l.Load15, perCore,
+		l.ProcsRunning, l.ProcsBlocked, FormatUptime(l.Uptime))
+}
+
+// MemoryBar renders memory as a segmented bar: used by applications ('u'), kernel
+// buffers ('b'), page cache ('c') and free (blank). RamStat.Used is Total - Available,
+// which overlaps the cache, so the application segment is what's left of the non-free
+// memory once buffers and cache are taken out.
+func MemoryBar(r hundler.RamStat, width int) string {
+	if r.Total == 0 {
+		return Bar(0, width)
+	}
+	var apps uint64
+	if other := r.Free + r.Buffers + r.Cached; other < r.Total {
+		apps = r.Total - other
+	}
+	cells := func(b uint64) int {
+		return int(float64(b)/float64(r.Total)*float64(width) + 0.5)
+	}
+
+	var bar strings.Builder
+	used := 0
+	for _, seg := range []struct {
+		glyph string
+		bytes uint64
+	}{
+		{"u", apps},
+		{"b", r.Buffers},
+		{"c", r.Cached},
+	} {
+		n := cells(seg.bytes)
+		if used+n > width {
+			n = width - used
+		}
+		bar.WriteString(strings.Repeat(seg.glyph, n))
+		used += n
+	}
+	bar.WriteString(strings.Repeat(" ", width-used))
+	return "[" + bar.String() + "]"
+}
+
+// PressurePanel renders Pressure Stall Information as one row per resource.
+func PressurePanel(p hundler.PressureStat) string {
+	s := fmt.Sprintf("Pressure:      %-8s %20s   %20s\n", "", "some 10s/60s/300s", "full 10s/60s/300s")
+	for _, r := range []struct {
+		name string
+		res  hundler.PressureResource
+	}{
+		{"cpu", p.CPU},
+		{"memory", p.Memory},
+		{"io", p.IO},
+	} {
+		if !r.res.Available {
+			s += fmt.Sprintf("               %-8s %20s\n", r.name, "n/a")
+			continue
+		}
+		full := "-"
+		if r.res.HasFull {
+			full = fmt.Sprintf("%6.2f %6.2f %6.2f", r.res.Full.Avg10, r.res.Full.Avg60, r.res.Full.Avg300)
+		}
+		s += fmt.Sprintf("               %-8s %6.2f %6.2f %6.2f   %20s   stalled %v\n", r.name,
+			r.res.Some.Avg10, r.res.Some.Avg60, r.res.Some.Avg300, full, r.res.Some.Delta.Round(time.Millisecond))
+	}
+	return s
+}
+
+// DiskIOPanel renders per-device I/O rates as a table.
+func DiskIOPanel(stats []hundler.DiskIOStat) string {
+	s := fmt.Sprintf("Disk I/O:      %-10s %10s %10s %8s %8s %8s %6s\n", "DEVICE", "READ/s", "WRITE/s", "R IOPS", "W IOPS", "AWAIT", "UTIL")
+	if len(stats) == 0 {
+		return s + "               (no devices)\n"
+	}
+	for _, d := range stats {
+		s += fmt.Sprintf("               %-10s %10s %10s %8.1f %8.1f %6.1fms %5.1f%%\n", d.Name,
+			ByteCountSI(uint64(d.ReadBytesPerSec)), ByteCountSI(uint64(d.WriteBytesPerSec)),
+			d.ReadOpsPerSec, d.WriteOpsPerSec, d.AwaitMs, d.UtilPercent)
+	}
+	return s
+}
+
+// DiskTable renders the usage of every monitored filesystem as a table. Rows that reached
+// one of the alert thresholds are marked with '!' and explained below the table.
+func DiskTable(disks []hundler.DiskStat, thresholds hundler.DiskThresholds) string {
+	s := fmt.Sprintf("Disks:        %-20s %-8s %9s %9s %9s %7s %7s\n", " MOUNT", "FSTYPE", "SIZE", "USED", "FREE", "USE%", "INODE%")
+	if len(disks) == 0 {
+		return s + "               (no filesystems)\n"
+	}
+	var alerts []string
+	for _, d := range disks {
+		marker := " "
+		if a := d.Alerts(thresholds); len(a) > 0 {
+			marker = "!"
+			alerts = append(alerts, a...)
+		}
+		inodes := "-"
+		if d.InodesTotal > 0 {
+			inodes = fmt.Sprintf("%.2f%%", d.InodesUsedPercent)
+		}
+		s += fmt.Sprintf("              %s%-20s %-8s %9s %9s %9s %6.2f%% %7s\n", marker, d.Path, d.Fstype,
+			ByteCountSI(d.Total), ByteCountSI(d.Used), ByteCountSI(d.Free), d.UsedPercent, inodes)
+	}
+	for _, a := range alerts {
+		s += "               ALERT: " + a + "\n"
+	}
+	return s
+}
+
+// InterfaceTable renders per-interface traffic, packet, error and drop rates as a table.
+func InterfaceTable(ifaces []hundler.InterfaceStat) string {
+	s := fmt.Sprintf("Interfaces:    %-12s %10s %10s %9s %9s %11s %11s\n", "NAME", "TX/s", "RX/s", "TX pkt/s", "RX pkt/s", "ERR in/out", "DROP in/out")
+	if len(ifaces) == 0 {
+		return s + "               (no interfaces)\n"
+	}
+	for _, i := range ifaces {
+		s += fmt.Sprintf("               %-12s %10s %10s %9.1f %9.1f %5.1f/%-5.1f %5.1f/%-5.1f\n", i.Name,
+			ByteCountSI(uint64(i.BytesSentPerSec)), ByteCountSI(uint64(i.BytesRecvPerSec)),
+			i.PacketsSentPerSec, i.PacketsRecvPerSec, i.ErrinPerSec, i.ErroutPerSec, i.DropinPerSec, i.DropoutPerSec)
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"testing"
+	"time"
+)
+
+func TestMemoryBar(t *testing.T) {
+	// Used (Total - Available) counts shared memory that is part of Cached as well.
+	r := hundler.RamStat{Total: 100, Used: 40, Free: 40, Buffers: 10, Cached: 30, Available: 60}
+	if got, want := MemoryBar(r, 10), "[uubccc    ]"; got != want {
+		t.Errorf("MemoryBar = %q, want %q", got, want)
+	}
+
+	// More buffers and cache than non-free memory leaves no application segment.
+	r = hundler.RamStat{Total: 100, Free: 50, Buffers: 20, Cached: 40}
+	if got, want := MemoryBar(r, 10), "[bbcccc    ]"; got != want {
+		t.Errorf("MemoryBar = %q, want %q", got, want)
+	}
+	if got := MemoryBar(hundler.RamStat{}, 4); got != Bar(0, 4) {
+		t.Errorf("MemoryBar of no memory = %q", got)
+	}
+}
+
+func TestFormatStart(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		start time.Time
+		want  string
+	}{
+		{now.Add(-time.Minute), "11:59:00"},
+		{now.Add(-23 * time.Hour), "13:00:00"}, // Yesterday, but within 24 hours
+		{now.Add(-25 * time.Hour), "Apr30"},
+		{now.AddDate(0, -11, 0), "Jun01"},
+		{now.AddDate(-2, 0, 0), "2022"},
+	}
+	for _, tt := range tests {
+		if got := formatStart(tt.start, now); got != tt.want {
+			t.Errorf("formatStart(%v) = %q, want %q", tt.start, got, tt.want)
+		}
+	}
+}
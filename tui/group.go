@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// groupings are the process groupings cycled with 'o', starting with none.
+var groupings = []hundler.GroupBy{"", hundler.GroupByName, hundler.GroupByUser, hundler.GroupByCgroup}
+
+// showingGroups reports whether the process table lists groups rather than processes.
+func (m MainModel) showingGroups() bool {
+	return m.groupBy != "" && m.drill == ""
+}
+
+// cycleGrouping switches to the next grouping and returns to its group list.
+func (m *MainModel) cycleGrouping() {
+	for i, by := range groupings {
+		if by == m.groupBy {
+			m.groupBy = groupings[(i+1)%len(groupings)]
+			break
+		}
+	}
+	m.drill = ""
+	m.applyFilter()
+}
+
+// drillIntoGroup lists the members of the selected group.
+func (m *MainModel) drillIntoGroup() {
+	if !m.showingGroups() || len(m.groups) == 0 {
+		return
+	}
+	m.drill = m.groups[m.cursor].Key
+	m.cursor, m.offset = 0, 0
+	m.applyFilter()
+}
+
+// leaveGroup returns from a group's members to the group list.
+func (m *MainModel) leaveGroup() {
+	if m.drill == "" {
+		return
+	}
+	m.drill = ""
+	m.applyFilter()
+}
+
+// groupLess returns the ordering of groups for the current sortBy and sortOrder.
+// Sorting by pid or open files orders groups by their number of processes.
+func (m *MainModel) groupLess() func(a, b hundler.ProcessGroup) bool {
+	return func(a, b hundler.ProcessGroup) bool {
+		var less bool
+		switch m.sortBy {
+		case "name":
+			less = a.Key < b.Key
+		case "cpu":
+			less = a.CPUPercent < b.CPUPercent
+		case "mem":
+			less = a.MemoryBytes < b.MemoryBytes
+		case "read":
+			less = a.ReadBytesPerSec < b.ReadBytesPerSec
+		case "write":
+			less = a.WriteBytesPerSec < b.WriteBytesPerSec
+		default:
+			less = a.Count < b.Count
+		}
+
+		if m.sortOrder == -1 {
+			return !less
+		}
+		return less
+	}
+}
+
+// sortGroups orders groups for display.
+func (m *MainModel) sortGroups() {
+	less := m.groupLess()
+	sort.SliceStable(m.groups, func(i, j int) bool { return less(m.groups[i], m.groups[j]) })
+}
+
+// groupMembers returns the processes in procs that belong to the drilled-into group.
+func (m MainModel) groupMembers(procs []hundler.ProcessStat) []hundler.ProcessStat {
+	var members []hundler.ProcessStat
+	for _, p := range procs {
+		if hundler.GroupKey(p, m.groupBy) == m.drill {
+			members = append(members, p)
+		}
+	}
+	return members
+}
+
+// groupTitle describes the grouping shown above the process table.
+func (m MainModel) groupTitle() string {
+	switch {
+	case m.groupBy == "":
+		return ""
+	case m.drill != "":
+		return fmt.Sprintf("Group: %s=%s  (esc to go back)   ", m.groupBy, m.drill)
+	default:
+		return fmt.Sprintf("Groups by %s: %d  (enter to open)   ", m.groupBy, len(m.groups))
+	}
+}
+
+// groupHeader renders the header line of the group table.
+func groupHeader() string {
+	return fmt.Sprintf("%-40s %5s %8s %9s %9s %9s", "GROUP", "COUNT", "CPU%", "MEM", "IO READ", "IO WRITE")
+}
+
+// groupRow renders one group as a line of the group table.
+func groupRow(g hundler.ProcessGroup) string {
+	row := fmt.Sprintf("%-40s %5d %8s %9s %9s %9s", truncate(g.Key, 40), g.Count,
+		fmt.Sprintf("%.2f%%", g.CPUPercent), ByteCountSI(g.MemoryBytes),
+		ByteCountSI(uint64(g.ReadBytesPerSec))+"/s", ByteCountSI(uint64(g.WriteBytesPerSec))+"/s")
+	return strings.TrimRight(row, " ")
+}
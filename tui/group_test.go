@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"strings"
+	"testing"
+)
+
+func TestGroupByNameAndDrillDown(t *testing.T) {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true})
+	next, _ := m.Update(processMsg(treeFixture()))
+	m = next.(MainModel)
+
+	m = press(m, "o")
+	if m.groupBy != hundler.GroupByName || len(m.groups) != 5 {
+		t.Fatalf("groupBy = %q with %d groups, want name with 5", m.groupBy, len(m.groups))
+	}
+	// Groups are sorted by CPU like processes, so the two node processes come first.
+	node := m.groups[m.cursor]
+	if node.Key != "node" || node.Count != 2 || node.CPUPercent != 80 || node.MemoryBytes != 800 {
+		t.Errorf("selected group = %+v, want node with 2 processes, 80%% CPU, 800 bytes", node)
+	}
+	if !strings.Contains(m.View(), "Groups by name: 5") {
+		t.Error("view should show the group list")
+	}
+	// Process actions need a process, not a group.
+	if m = press(m, "x"); m.prompt != nil {
+		t.Error("signal prompt opened on a group row")
+	}
+
+	m = press(m, "enter")
+	if m.drill != "node" || len(m.Processes) != 2 || m.Processes[m.cursor].Pid != 12 {
+		t.Fatalf("drill = %q with %d rows, want node's 2 members", m.drill, len(m.Processes))
+	}
+	// A refresh keeps showing the group's members.
+	next, _ = m.Update(processMsg(treeFixture()))
+	m = next.(MainModel)
+	if len(m.Processes) != 2 {
+		t.Errorf("after refresh %d rows, want 2", len(m.Processes))
+	}
+
+	m = press(m, "esc")
+	if m.drill != "" || m.groups[m.cursor].Key != "node" {
+		t.Errorf("esc should return to the group list with node selected, drill = %q", m.drill)
+	}
+	m = press(m, "o", "o", "o")
+	if m.groupBy != "" || len(m.Processes) != 6 {
+		t.Errorf("cycling back should ungroup, groupBy = %q, %d rows", m.groupBy, len(m.Processes))
+	}
+}
@@ -2,55 +2,140 @@ package tui
 
 import (
 	"basicsystemmonitor/hundler"
+	"context"
 	"fmt"
 	"sort" // Import the sort package
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // MainModel holds the state of the entire TUI application.
 type MainModel struct {
 	cpuCh  <-chan hundler.CpuStat
 	ramCh  <-chan hundler.RamStat
-	diskCh <-chan hundler.DiskStat
+	diskCh <-chan []hundler.DiskStat
+	ioCh   <-chan []hundler.DiskIOStat
 	netCh  <-chan hundler.NetStat
+	ifCh   <-chan []hundler.InterfaceStat
+	loadCh <-chan hundler.LoadStat
+	psiCh  <-chan hundler.PressureStat
 	procCh <-chan []hundler.ProcessStat // New: Channel for process stats
 
-	CpuStat       hundler.CpuStat
-	RamStat       hundler.RamStat
-	DiskStat      hundler.DiskStat
-	NetStat       hundler.NetStat
-	Processes     []hundler.ProcessStat // New: Current list of processes
-	LastUpdate    time.Time
+	CpuStat      hundler.CpuStat
+	RamStat      hundler.RamStat
+	Disks        []hundler.DiskStat
+	DiskIO       []hundler.DiskIOStat
+	NetStat      hundler.NetStat
+	Interfaces   []hundler.InterfaceStat
+	LoadStat     hundler.LoadStat
+	Pressure     hundler.PressureStat
+	Processes    []hundler.ProcessStat // Visible rows of the process table, in display order
+	Matched      []hundler.ProcessStat // Processes matching the filter
+	AllProcesses []hundler.ProcessStat // Every process from the latest refresh
+	LastUpdate   time.Time
 
-	sortBy        string // "cpu", "mem", "pid", "name"
-	sortOrder     int    // 1 for ascending, -1 for descending
-	ifaceName     string // New: Stores the name of the monitored network interface
-	showProcesses bool   // New: Toggles process list visibility
+	sortBy    string // "cpu", "mem", "pid", "name", "read", "write", "fds"
+	sortOrder int    // 1 for ascending, -1 for descending
+	opts      Options
+
+	width, height int   // Terminal size, zero until the first tea.WindowSizeMsg
+	cursor        int   // Index of the selected row in Processes
+	offset        int   // Index of the first visible row in Processes
+	selectedPid   int32 // Pid of the selected process, kept across refreshes and re-sorts
+
+	treeMode  bool           // Show processes as a tree built from parent pids
+	tree      []treeRow      // Tree layout of Processes while treeMode is set
+	collapsed map[int32]bool // Pids whose subtrees are folded in tree mode
+
+	groupBy       hundler.GroupBy        // Grouping of the process list, empty for none
+	groups        []hundler.ProcessGroup // Groups of the processes matching the filter, in display order
+	drill         string                 // Key of the group whose members are listed, empty for the group list
+	selectedGroup string                 // Key of the selected group, kept across refreshes and re-sorts
+
+	history map[int32]*processHistory // CPU and RSS history per pid for the detail pane
+	detail  *detailPane               // Open detail pane, if any
+
+	filter      hundler.ProcessFilter
+	filtering   bool   // True while the filter is being typed after '/'
+	filterInput string // Filter text being edited
+	filterErr   error  // Parse error of filterInput, if any
+
+	signals hundler.SignalSender
+	control hundler.ProcessController
+	prompt  *signalPrompt // Open signal picker, if any
+	input   *inputPrompt  // Open renice/ionice/affinity prompt, if any
+	status  string        // Outcome of the last action, shown in the footer
+}
+
+var selectedRowStyle = lipgloss.NewStyle().Reverse(true)
+
+// Options holds the display settings of the TUI.
+type Options struct {
+	IfaceName      string                    // Name of the monitored network interface, empty for all
+	ShowProcesses  bool                      // Toggles process list visibility
+	DiskAlerts     hundler.DiskThresholds    // Usage at which filesystems are flagged
+	ProcessColumns []string                  // Process table columns, see DefaultProcessColumns
+	ProcessFilter  string                    // Initial process filter, see hundler.ProcessFilter
+	SignalSender   hundler.SignalSender      // Delivers signals; defaults to hundler.SystemSignalSender
+	Controller     hundler.ProcessController // Changes priorities; defaults to hundler.SystemProcessController
+
+	// DetailMonitor starts collecting the details of a process for the detail pane until
+	// ctx is cancelled; defaults to hundler.StartProcessDetailMonitor every second.
+	DetailMonitor func(ctx context.Context, pid int32) <-chan hundler.ProcessDetail
 }
 
 // New creates a new MainModel with the given channels.
-func New(cpuCh <-chan hundler.CpuStat, ramCh <-chan hundler.RamStat, diskCh <-chan hundler.DiskStat, netCh <-chan hundler.NetStat, procCh <-chan []hundler.ProcessStat, ifaceName string, showProcesses bool) MainModel {
+func New(cpuCh <-chan hundler.CpuStat, ramCh <-chan hundler.RamStat, diskCh <-chan []hundler.DiskStat, ioCh <-chan []hundler.DiskIOStat, netCh <-chan hundler.NetStat, ifCh <-chan []hundler.InterfaceStat, loadCh <-chan hundler.LoadStat, psiCh <-chan hundler.PressureStat, procCh <-chan []hundler.ProcessStat, opts Options) MainModel {
+	if len(opts.ProcessColumns) == 0 {
+		opts.ProcessColumns = DefaultProcessColumns
+	}
+	// The filter is validated by the caller; an invalid one simply starts out empty.
+	filter, _ := hundler.ParseProcessFilter(opts.ProcessFilter)
+	if opts.SignalSender == nil {
+		opts.SignalSender = hundler.SystemSignalSender{}
+	}
+	if opts.Controller == nil {
+		opts.Controller = hundler.SystemProcessController{}
+	}
+	if opts.DetailMonitor == nil {
+		opts.DetailMonitor = func(ctx context.Context, pid int32) <-chan hundler.ProcessDetail {
+			return hundler.StartProcessDetailMonitor(ctx, time.Second, pid, "/proc")
+		}
+	}
 	return MainModel{
-		cpuCh:         cpuCh,
-		ramCh:         ramCh,
-		diskCh:        diskCh,
-		netCh:         netCh,
-		procCh:        procCh, // New: Store process channel
-		LastUpdate:    time.Now(),
-		sortBy:        "cpu", // Default sort by CPU
-		sortOrder:     -1,    // Default descending
-		ifaceName:     ifaceName, // New: Store network interface name
-		showProcesses: showProcesses, // New: Store process list visibility
+		cpuCh:      cpuCh,
+		ramCh:      ramCh,
+		diskCh:     diskCh,
+		ioCh:       ioCh,
+		netCh:      netCh,
+		ifCh:       ifCh,
+		loadCh:     loadCh,
+		psiCh:      psiCh,
+		procCh:     procCh, // New: Store process channel
+		LastUpdate: time.Now(),
+		sortBy:     "cpu", // Default sort by CPU
+		sortOrder:  -1,    // Default descending
+		opts:       opts,
+		filter:     filter,
+		collapsed:  make(map[int32]bool),
+		history:    make(map[int32]*processHistory),
+		signals:    opts.SignalSender,
+		control:    opts.Controller,
 	}
 }
 
 // Msg types for updating the model
 type cpuMsg hundler.CpuStat
 type ramMsg hundler.RamStat
-type diskMsg hundler.DiskStat
+type diskMsg []hundler.DiskStat
+type diskIOMsg []hundler.DiskIOStat
 type netMsg hundler.NetStat
+type interfacesMsg []hundler.InterfaceStat
+type loadMsg hundler.LoadStat
+type pressureMsg hundler.PressureStat
 type processMsg []hundler.ProcessStat // New: Message type for process stats
 type tickMsg time.Time
 
@@ -64,8 +149,16 @@ func (m *MainModel) waitForActivity() tea.Cmd {
 			return ramMsg(ram)
 		case disk := <-m.diskCh:
 			return diskMsg(disk)
+		case io := <-m.ioCh:
+			return diskIOMsg(io)
 		case net := <-m.netCh:
 			return netMsg(net)
+		case ifaces := <-m.ifCh:
+			return interfacesMsg(ifaces)
+		case load := <-m.loadCh:
+			return loadMsg(load)
+		case psi := <-m.psiCh:
+			return pressureMsg(psi)
 		case procs := <-m.procCh: // New: Listen for process updates
 			return processMsg(procs)
 		}
@@ -86,70 +179,300 @@ func (m MainModel) Init() tea.Cmd {
 	)
 }
 
-// sortProcesses sorts the process list based on the current sortBy and sortOrder.
+// processLess returns the ordering of processes for the current sortBy and sortOrder.
+func (m *MainModel) processLess() func(a, b hundler.ProcessStat) bool {
+	less, err := hundler.ProcessLess(m.sortBy)
+	if err != nil {
+		less, _ = hundler.ProcessLess("cpu")
+	}
+	if m.sortOrder == -1 {
+		return func(a, b hundler.ProcessStat) bool { return less(b, a) }
+	}
+	return less
+}
+
+// sortProcesses rebuilds Processes from Matched based on the current sortBy and
+// sortOrder, keeping the selected process under the cursor. In tree mode the order
+// applies among siblings.
 func (m *MainModel) sortProcesses() {
-	sort.Slice(m.Processes, func(i, j int) bool {
-		var less bool
-		switch m.sortBy {
-		case "pid":
-			less = m.Processes[i].Pid < m.Processes[j].Pid
-		case "name":
-			less = m.Processes[i].Name < m.Processes[j].Name
-		case "cpu":
-			less = m.Processes[i].CPUPercent < m.Processes[j].CPUPercent
-		case "mem":
-			less = m.Processes[i].MemoryBytes < m.Processes[j].MemoryBytes
-		default:
-			less = m.Processes[i].CPUPercent < m.Processes[j].CPUPercent
-		}
-
-		if m.sortOrder == -1 {
-			return !less
-		}
-		return less
-	})
+	if m.groupBy != "" {
+		m.sortGroups()
+	}
+	less := m.processLess()
+	if m.treeMode {
+		m.Processes, m.tree = buildProcessTree(m.Matched, less, m.collapsed)
+	} else {
+		m.Processes = append([]hundler.ProcessStat(nil), m.Matched...)
+		sort.Slice(m.Processes, func(i, j int) bool { return less(m.Processes[i], m.Processes[j]) })
+		m.tree = nil
+	}
+	m.followSelection()
+}
+
+// toggleTree switches between the flat process list and the process tree.
+func (m *MainModel) toggleTree() {
+	m.treeMode = !m.treeMode
+	m.sortProcesses()
+}
+
+// setCollapsed folds or unfolds the subtree of the selected process in tree mode.
+// When toggle is set the current state is flipped and collapse is ignored.
+func (m *MainModel) setCollapsed(collapse, toggle bool) {
+	if !m.treeMode || m.showingGroups() || len(m.Processes) == 0 || m.tree[m.cursor].descendants == 0 {
+		return
+	}
+	pid := m.Processes[m.cursor].Pid
+	if toggle {
+		collapse = !m.collapsed[pid]
+	}
+	if collapse {
+		m.collapsed[pid] = true
+	} else {
+		delete(m.collapsed, pid)
+	}
+	m.sortProcesses()
+}
+
+// pruneCollapsed forgets folded subtrees of processes that have exited.
+func (m *MainModel) pruneCollapsed() {
+	alive := make(map[int32]bool, len(m.AllProcesses))
+	for _, p := range m.AllProcesses {
+		alive[p.Pid] = true
+	}
+	for pid := range m.collapsed {
+		if !alive[pid] {
+			delete(m.collapsed, pid)
+		}
+	}
+}
+
+// rowCount returns the number of rows in the process table: groups or processes.
+func (m MainModel) rowCount() int {
+	if m.showingGroups() {
+		return len(m.groups)
+	}
+	return len(m.Processes)
+}
+
+// followSelection moves the cursor back onto the selected pid or group after the list
+// was refreshed or re-sorted. If it is gone, the cursor stays at the same row.
+func (m *MainModel) followSelection() {
+	if m.rowCount() == 0 {
+		m.cursor, m.offset = 0, 0
+		return
+	}
+	for i := 0; i < m.rowCount(); i++ {
+		if m.showingGroups() && m.groups[i].Key == m.selectedGroup || !m.showingGroups() && m.Processes[i].Pid == m.selectedPid {
+			m.cursor = i
+			m.scrollToCursor()
+			return
+		}
+	}
+	m.moveCursor(0)
+}
+
+// moveCursor moves the selection by delta rows, clamped to the list.
+func (m *MainModel) moveCursor(delta int) {
+	n := m.rowCount()
+	if n == 0 {
+		return
+	}
+	m.cursor += delta
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.showingGroups() {
+		m.selectedGroup = m.groups[m.cursor].Key
+	} else {
+		m.selectedPid = m.Processes[m.cursor].Pid
+	}
+	m.scrollToCursor()
+}
+
+// scrollToCursor adjusts the scroll offset so that the cursor row is visible.
+func (m *MainModel) scrollToCursor() {
+	m.offset = m.scrollOffset(m.processListHeight())
+}
+
+// scrollOffset returns the scroll offset that keeps the cursor row visible in a list of
+// height rows, moving the current offset as little as possible.
+func (m MainModel) scrollOffset(height int) int {
+	offset := m.offset
+	if m.cursor < offset {
+		offset = m.cursor
+	}
+	if m.cursor >= offset+height {
+		offset = m.cursor - height + 1
+	}
+	if maxOffset := m.rowCount() - height; offset > maxOffset {
+		offset = max(maxOffset, 0)
+	}
+	return offset
+}
+
+// applyFilter rebuilds Matched and Processes from AllProcesses using the current filter and sort order.
+func (m *MainModel) applyFilter() {
+	m.Matched = m.filter.Apply(m.AllProcesses)
+	m.groups = nil
+	if m.groupBy != "" {
+		m.groups = hundler.GroupProcesses(m.Matched, m.groupBy)
+		if m.drill != "" {
+			m.Matched = m.groupMembers(m.Matched)
+		}
+	}
+	m.sortProcesses()
+}
+
+// updateFilter handles key presses while the filter is being edited. The list is
+// narrowed as you type; enter keeps the filter and esc clears it.
+func (m MainModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		if m.filterErr == nil {
+			m.filtering = false
+		}
+		return m, nil
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filterInput = ""
+		m.filterErr = nil
+	case tea.KeyBackspace:
+		if r := []rune(m.filterInput); len(r) > 0 {
+			m.filterInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.filterInput += " "
+	case tea.KeyRunes:
+		m.filterInput += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	filter, err := hundler.ParseProcessFilter(m.filterInput)
+	m.filterErr = err
+	if err == nil {
+		m.filter = filter
+		m.applyFilter()
+	}
+	return m, nil
+}
+
+// filterStatus renders the status line describing the active or edited filter.
+func (m MainModel) filterStatus() string {
+	switch {
+	case m.filtering && m.filterErr != nil:
+		return fmt.Sprintf("Filter: /%s█  (%v)", m.filterInput, m.filterErr)
+	case m.filtering:
+		return fmt.Sprintf("Filter: /%s█  (%d of %d, enter to keep, esc to clear)", m.filterInput, len(m.Matched), len(m.AllProcesses))
+	case !m.filter.Empty():
+		return fmt.Sprintf("Filter: %s  (%d of %d, '/' to edit)", m.filter, len(m.Matched), len(m.AllProcesses))
+	default:
+		return "Filter: none  ('/' to filter)"
+	}
+}
+
+// toggleSort sorts by key, flipping the order if the list is already sorted by it.
+// PIDs and names default to ascending; the other keys to descending so the heaviest
+// processes come first.
+func (m *MainModel) toggleSort(key string) {
+	if m.sortBy == key {
+		m.sortOrder *= -1
+	} else {
+		m.sortBy = key
+		m.sortOrder = -1
+		if key == "pid" || key == "name" {
+			m.sortOrder = 1
+		}
+	}
+	m.sortProcesses()
 }
 
 // Update handles messages and updates the model accordingly.
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.detail != nil {
+			return m.updateDetail(msg)
+		}
+		if m.prompt != nil {
+			return m.updateSignalPrompt(msg)
+		}
+		if m.input != nil {
+			return m.updateInputPrompt(msg)
+		}
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "/":
+			m.filtering = true
+			m.filterInput = m.filter.String()
+			m.filterErr = nil
 		case "c": // Sort by CPU
-			if m.sortBy == "cpu" {
-				m.sortOrder *= -1 // Toggle order
-			} else {
-				m.sortBy = "cpu"
-				m.sortOrder = -1 // Default descending for CPU
-			}
-			m.sortProcesses() // Sort immediately after key press
+			m.toggleSort("cpu")
 		case "m": // Sort by Memory
-			if m.sortBy == "mem" {
-				m.sortOrder *= -1 // Toggle order
-			} else {
-				m.sortBy = "mem"
-				m.sortOrder = -1 // Default descending for Memory
-			}
-			m.sortProcesses() // Sort immediately after key press
+			m.toggleSort("mem")
 		case "p": // Sort by PID
-			if m.sortBy == "pid" {
-				m.sortOrder *= -1 // Toggle order
-			} else {
-				m.sortBy = "pid"
-				m.sortOrder = 1 // Default ascending for PID
-			}
-			m.sortProcesses() // Sort immediately after key press
+			m.toggleSort("pid")
 		case "n": // Sort by Name
-			if m.sortBy == "name" {
-				m.sortOrder *= -1 // Toggle order
+			m.toggleSort("name")
+		case "r": // Sort by IO read rate
+			m.toggleSort("read")
+		case "w": // Sort by IO write rate
+			m.toggleSort("write")
+		case "f": // Sort by open file descriptors
+			m.toggleSort("fds")
+		case "x": // Signal the selected process
+			m.openSignalPrompt(false)
+		case "X": // Signal every process matching the filter
+			m.openSignalPrompt(true)
+		case "N": // Renice the selected process
+			m.openNicePrompt()
+		case "I": // Change the I/O priority of the selected process
+			m.openIOPriorityPrompt()
+		case "A": // Change the CPU affinity of the selected process
+			m.openAffinityPrompt()
+		case "t": // Toggle the process tree
+			m.toggleTree()
+		case "o": // Cycle the grouping: none, name, user, cgroup
+			m.cycleGrouping()
+		case "enter": // List the members of the selected group, or show the selected process
+			if m.showingGroups() {
+				m.drillIntoGroup()
 			} else {
-				m.sortBy = "name"
-				m.sortOrder = 1 // Default ascending for Name
+				return m, m.openDetail()
 			}
-			m.sortProcesses() // Sort immediately after key press
+		case "esc", "backspace": // Back to the group list
+			m.leaveGroup()
+		case " ": // Fold or unfold the selected subtree
+			m.setCollapsed(false, true)
+		case "left":
+			m.setCollapsed(true, false)
+		case "right":
+			m.setCollapsed(false, false)
+		case "up", "k":
+			m.moveCursor(-1)
+		case "down", "j":
+			m.moveCursor(1)
+		case "pgup":
+			m.moveCursor(-m.processListHeight())
+		case "pgdown":
+			m.moveCursor(m.processListHeight())
+		case "home", "g":
+			m.moveCursor(-m.rowCount())
+		case "end", "G":
+			m.moveCursor(m.rowCount())
 		}
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scrollToCursor()
 	case cpuMsg:
 		m.CpuStat = hundler.CpuStat(msg)
 		return m, m.waitForActivity()
@@ -157,15 +480,40 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.RamStat = hundler.RamStat(msg)
 		return m, m.waitForActivity()
 	case diskMsg:
-		m.DiskStat = hundler.DiskStat(msg)
+		m.Disks = []hundler.DiskStat(msg)
+		return m, m.waitForActivity()
+	case diskIOMsg:
+		m.DiskIO = []hundler.DiskIOStat(msg)
 		return m, m.waitForActivity()
 	case netMsg:
 		m.NetStat = hundler.NetStat(msg)
 		return m, m.waitForActivity()
+	case interfacesMsg:
+		m.Interfaces = []hundler.InterfaceStat(msg)
+		return m, m.waitForActivity()
+	case loadMsg:
+		m.LoadStat = hundler.LoadStat(msg)
+		return m, m.waitForActivity()
+	case pressureMsg:
+		m.Pressure = hundler.PressureStat(msg)
+		return m, m.waitForActivity()
 	case processMsg: // New: Handle process updates
-		m.Processes = processMsg(msg)
-		m.sortProcesses() // Sort after receiving new data
+		m.AllProcesses = processMsg(msg)
+		m.recordHistory()
+		m.pruneCollapsed()
+		m.applyFilter() // Filter and sort after receiving new data
 		return m, m.waitForActivity()
+	case detailMsg:
+		if m.detail == nil || m.detail.ch != msg.ch {
+			return m, nil // The pane was closed
+		}
+		d := msg.detail
+		m.detail.detail = &d
+		return m, waitForDetail(msg.ch)
+	case detailDoneMsg:
+		if m.detail != nil && m.detail.ch == msg.ch {
+			m.detail.exited = true
+		}
 	case tickMsg:
 		m.LastUpdate = time.Time(msg)
 		return m, tickCommand(time.Second)
@@ -173,30 +521,109 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// View renders the UI.
-func (m MainModel) View() string {
-	s := fmt.Sprintf("Basic System Monitor — %s\n\n", m.LastUpdate.Format(time.RFC1123))
+// renderSummary renders every panel above the process list.
+func (m MainModel) renderSummary() string {
+	s := fmt.Sprintf("Basic System Monitor — %s\n", m.LastUpdate.Format(time.RFC1123))
+	s += LoadLine(m.LoadStat) + "\n\n"
 
-	s += fmt.Sprintf("CPU:           %6.2f%% \n\n", m.CpuStat.Percent)
-	s += fmt.Sprintf("RAM:           %8s / %8s (%6.2f%%)\n\n", ByteCountSI(m.RamStat.Used), ByteCountSI(m.RamStat.Total), m.RamStat.UsedPercent)
-	s += fmt.Sprintf("Disk (/):      %8s (%6.2f%%) \n\n", ByteCountSI(m.DiskStat.Used), m.DiskStat.UsedPercent)
+	s += fmt.Sprintf("CPU:           %6.2f%% \n", m.CpuStat.Percent)
+	s += "CPU time:      " + StackedCpuBar(m.CpuStat.Times, 40) + "\n"
+	s += "               " + CpuTimesLegend(m.CpuStat.Times) + "\n"
+	s += CoreGrid(m.CpuStat.PerCore) + "\n"
+	s += fmt.Sprintf("RAM:           %8s / %8s (%6.2f%%)   avail %8s\n", ByteCountSI(m.RamStat.Used), ByteCountSI(m.RamStat.Total), m.RamStat.UsedPercent, ByteCountSI(m.RamStat.Available))
+	s += "               " + MemoryBar(m.RamStat, 40) + "\n"
+	s += fmt.Sprintf("               u:used b:buffers %s c:cache %s  free %s shared %s slab %s dirty %s writeback %s\n",
+		ByteCountSI(m.RamStat.Buffers), ByteCountSI(m.RamStat.Cached), ByteCountSI(m.RamStat.Free), ByteCountSI(m.RamStat.Shared),
+		ByteCountSI(m.RamStat.Slab), ByteCountSI(m.RamStat.Dirty), ByteCountSI(m.RamStat.WriteBack))
+	s += fmt.Sprintf("Swap:          %8s / %8s (%6.2f%%)   in %8s/s   out %8s/s\n\n", ByteCountSI(m.RamStat.SwapUsed), ByteCountSI(m.RamStat.SwapTotal), m.RamStat.SwapUsedPercent,
+		ByteCountSI(uint64(m.RamStat.SwapInPerSec)), ByteCountSI(uint64(m.RamStat.SwapOutPerSec)))
+	s += DiskTable(m.Disks, m.opts.DiskAlerts) + "\n"
+	s += DiskIOPanel(m.DiskIO) + "\n"
+	s += PressurePanel(m.Pressure) + "\n"
 	netInfo := "Network:"
-	if m.ifaceName != "" {
-		netInfo += fmt.Sprintf(" (%s)", m.ifaceName)
+	if m.opts.IfaceName != "" {
+		netInfo += fmt.Sprintf(" (%s)", m.opts.IfaceName)
 	}
-	s += fmt.Sprintf("%-15s ↑ %8s/s   ↓ %8s/s\n\n", netInfo, ByteCountSI(uint64(m.NetStat.BytesSentPerSec)), ByteCountSI(uint64(m.NetStat.BytesRecvPerSec)))
+	s += fmt.Sprintf("%-15s ↑ %8s/s   ↓ %8s/s\n", netInfo, ByteCountSI(uint64(m.NetStat.BytesSentPerSec)), ByteCountSI(uint64(m.NetStat.BytesRecvPerSec)))
+	s += InterfaceTable(m.Interfaces) + "\n"
+	return s
+}
 
-	if m.showProcesses {
-		s += "Processes:\n"
-		s += fmt.Sprintf("%-8s %-30s %-8s %-8s\n", "PID", "NAME", "CPU%", "MEM")
-		for i, p := range m.Processes {
-			if i >= 10 { // Limit to 10 processes for brevity for now
-				break
+// processListHeight returns how many process rows fit below the summary panels.
+func (m MainModel) processListHeight() int {
+	_, rows := m.layout(m.renderSummary(), m.renderFooter())
+	return rows
+}
+
+// layout fits the summary panels and the process list between them and the footer into
+// the terminal height. It returns the summary, cut short if it would leave fewer than a
+// few rows for the list, and the number of process rows that fit below it.
+// Until the terminal size is known, a fixed number of rows is shown.
+func (m MainModel) layout(summary, footer string) (string, int) {
+	const minRows, defaultRows = 5, 10
+	if m.height == 0 || !m.opts.ShowProcesses {
+		return summary, defaultRows
+	}
+	// Two lines for the table title and header.
+	available := m.height - 2 - strings.Count(footer, "\n")
+	summaryLines := strings.Count(summary, "\n")
+	if keep := max(available-minRows, 0); summaryLines > keep {
+		summary = strings.Join(strings.SplitAfter(summary, "\n")[:keep], "")
+		summaryLines = keep
+	}
+	return summary, max(available-summaryLines, minRows)
+}
+
+// View renders the UI.
+func (m MainModel) View() string {
+	footer := m.renderFooter()
+	s, rows := m.layout(m.renderSummary(), footer)
+
+	if m.detail != nil {
+		s += m.detail.render(m.history[m.detail.pid], detailHeight(rows))
+	} else if m.opts.ShowProcesses {
+		s += fmt.Sprintf("Processes: %d   %s%s\n", len(m.Matched), m.groupTitle(), m.filterStatus())
+		if m.showingGroups() {
+			s += groupHeader() + "\n"
+		} else {
+			s += processHeader(m.opts.ProcessColumns) + "\n"
+		}
+		label := treeLabelColumn(m.opts.ProcessColumns)
+		// The summary may have grown since the last key press moved the offset.
+		offset := m.scrollOffset(rows)
+		end := min(offset+rows, m.rowCount())
+		for i := offset; i < end; i++ {
+			var row string
+			switch {
+			case m.showingGroups():
+				row = groupRow(m.groups[i])
+			case m.treeMode:
+				row = processRow(m.opts.ProcessColumns, m.tree[i].decorate(m.Processes[i], label))
+			default:
+				row = processRow(m.opts.ProcessColumns, m.Processes[i])
 			}
-			s += fmt.Sprintf("%-8d %-30s %-7.2f%% %s\n", p.Pid, p.Name, p.CPUPercent, ByteCountSI(p.MemoryBytes))
+			if i == m.cursor {
+				row = selectedRowStyle.Render(row)
+			}
+			s += row + "\n"
 		}
 	}
 
-	s += "\nPress 'q' or 'ctrl+c' to quit.\n"
+	return s + footer
+}
+
+// renderFooter renders the signal picker, the key help and the status bar.
+func (m MainModel) renderFooter() string {
+	var s string
+	if m.prompt != nil {
+		s += "\n" + m.prompt.render()
+	}
+	if m.input != nil {
+		s += "\n" + m.input.render()
+	}
+	s += "\nPress 'q' or 'ctrl+c' to quit, ↑/↓ j/k PgUp/PgDn Home/End to move, 'x'/'X' to signal, 'N'/'I'/'A' to renice/ionice/set affinity, 't' for the tree (space/←/→ to fold), 'o' to group, 'enter' for details.\n"
+	if m.status != "" {
+		s += m.status + "\n"
+	}
 	return s
 }
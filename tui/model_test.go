@@ -0,0 +1,296 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recordSignals returns a SignalSender that records signals in sent instead of sending
+// them, and fails for the denied pids.
+func recordSignals(sent map[int32]syscall.Signal, denied ...int32) hundler.SignalSender {
+	return hundler.SignalSenderFunc(func(pid int32, sig syscall.Signal) error {
+		if slices.Contains(denied, pid) {
+			return errors.New("operation not permitted")
+		}
+		sent[pid] = sig
+		return nil
+	})
+}
+
+func newTestModel(sender hundler.SignalSender) MainModel {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true, SignalSender: sender})
+	next, _ := m.Update(processMsg{
+		{Pid: 1, Name: "init", CPUPercent: 1},
+		{Pid: 200, Name: "node", CPUPercent: 90},
+		{Pid: 300, Name: "node", CPUPercent: 50},
+	})
+	return next.(MainModel)
+}
+
+// press feeds key presses to the model, one tea.KeyMsg per entry.
+func press(m MainModel, keys ...string) MainModel {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(MainModel)
+	}
+	return m
+}
+
+func TestSignalSelectedProcess(t *testing.T) {
+	sent := map[int32]syscall.Signal{}
+	m := newTestModel(recordSignals(sent))
+
+	// Sorted by CPU, pid 200 is selected; pick the second signal (SIGKILL) and confirm.
+	m = press(m, "x", "down", "enter", "y")
+	if got := sent[200]; got != hundler.Signals[1].Signal {
+		t.Errorf("pid 200 received %v, want %v", got, hundler.Signals[1].Signal)
+	}
+	if len(sent) != 1 {
+		t.Errorf("only the selected process should be signalled, sent %v", sent)
+	}
+	if !strings.Contains(m.status, "Sent "+hundler.Signals[1].Name+" to pid 200") {
+		t.Errorf("unexpected status %q", m.status)
+	}
+}
+
+func TestSignalCancelAndDenied(t *testing.T) {
+	sent := map[int32]syscall.Signal{}
+	m := newTestModel(recordSignals(sent, 200))
+
+	m = press(m, "x", "enter", "esc")
+	if len(sent) != 0 || m.prompt != nil {
+		t.Fatalf("cancelled prompt should not send anything, sent %v", sent)
+	}
+
+	m = press(m, "x", "enter", "y")
+	if !strings.Contains(m.status, "failed: operation not permitted") {
+		t.Errorf("permission error should be reported, status %q", m.status)
+	}
+}
+
+func TestSignalAllMatchingFilter(t *testing.T) {
+	sent := map[int32]syscall.Signal{}
+	m := newTestModel(recordSignals(sent))
+
+	// Without a filter, 'X' refuses to target every process.
+	m = press(m, "X")
+	if m.prompt != nil {
+		t.Fatal("'X' without a filter should not open the signal picker")
+	}
+
+	m = press(m, "/", "n", "o", "d", "e", "enter", "X", "enter", "y")
+	if len(sent) != 2 || sent[200] == 0 || sent[300] == 0 {
+		t.Errorf("both node processes should be signalled, sent %v", sent)
+	}
+	if !strings.Contains(m.status, "to 2 processes") {
+		t.Errorf("unexpected status %q", m.status)
+	}
+}
+
+// fakeController records priority changes instead of applying them and fails for denied pids.
+type fakeController struct {
+	nice     map[int32]int
+	ioClass  hundler.IOClass
+	ioLevel  int
+	affinity []int
+	denied   map[int32]bool
+}
+
+func (f *fakeController) Renice(pid int32, nice int) error {
+	if f.denied[pid] {
+		return os.ErrPermission
+	}
+	f.nice[pid] = nice
+	return nil
+}
+
+func (f *fakeController) SetIOPriority(pid int32, class hundler.IOClass, level int) error {
+	f.ioClass, f.ioLevel = class, level
+	return nil
+}
+
+func (f *fakeController) SetAffinity(pid int32, cpus []int) error {
+	f.affinity = cpus
+	return nil
+}
+
+func TestReniceSelectedProcess(t *testing.T) {
+	ctl := &fakeController{nice: map[int32]int{}, denied: map[int32]bool{300: true}}
+	m := newTestModel(nil)
+	m.control = ctl
+
+	// The prompt is prefilled with the current nice value (0); replace it with 10.
+	m = press(m, "N", "backspace", "1", "0", "enter")
+	if ctl.nice[200] != 10 {
+		t.Fatalf("nice of pid 200 = %v, want 10", ctl.nice)
+	}
+	if m.input != nil || !strings.Contains(m.status, "pid 200") {
+		t.Errorf("status = %q, input = %v", m.status, m.input)
+	}
+
+	m = press(m, "N", "backspace", "5", "0", "enter")
+	if !strings.Contains(m.status, "invalid nice value") {
+		t.Errorf("status = %q, want invalid nice value", m.status)
+	}
+
+	m = press(m, "down", "N", "enter")
+	if _, ok := ctl.nice[300]; ok || !strings.Contains(m.status, "CAP_SYS_NICE") {
+		t.Errorf("denied renice: nice = %v, status = %q", ctl.nice, m.status)
+	}
+}
+
+func TestIOPriorityAndAffinity(t *testing.T) {
+	ctl := &fakeController{}
+	m := newTestModel(nil)
+	m.control = ctl
+
+	m = press(m, "I", "i", "d", "l", "e", "enter")
+	if ctl.ioClass != hundler.IOClassIdle {
+		t.Errorf("io class = %v, want idle (status %q)", ctl.ioClass, m.status)
+	}
+	m = press(m, "A", "0", "-", "2", "enter")
+	if len(ctl.affinity) != 3 || ctl.affinity[2] != 2 {
+		t.Errorf("affinity = %v, want [0 1 2] (status %q)", ctl.affinity, m.status)
+	}
+	m = press(m, "A", "x", "esc")
+	if m.input != nil || m.status != "Cancelled" {
+		t.Errorf("esc: status = %q, input = %v", m.status, m.input)
+	}
+}
+
+// manyProcesses returns n processes ordered by CPU usage, pid 1 the busiest.
+func manyProcesses(n int) processMsg {
+	procs := make(processMsg, n)
+	for i := range procs {
+		procs[i] = hundler.ProcessStat{Pid: int32(i + 1), Name: "proc", CPUPercent: float64(n - i)}
+	}
+	return procs
+}
+
+func update(m MainModel, msg tea.Msg) MainModel {
+	next, _ := m.Update(msg)
+	return next.(MainModel)
+}
+
+func TestScrollAtFixedHeight(t *testing.T) {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true})
+	m = update(m, manyProcesses(200))
+	m = update(m, tea.WindowSizeMsg{Width: 120, Height: 60})
+
+	rows := m.processListHeight()
+	if got := strings.Count(m.View(), "\n"); got != 60 {
+		t.Fatalf("view has %d lines, want it to fill the 60 line terminal", got)
+	}
+
+	// Moving past the last visible row scrolls by one.
+	for i := 0; i < rows; i++ {
+		m = press(m, "j")
+	}
+	if m.cursor != rows || m.offset != 1 {
+		t.Errorf("after %d moves: cursor %d, offset %d, want %d and 1", rows, m.cursor, m.offset, rows)
+	}
+	m = update(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.cursor != 2*rows || m.offset != rows+1 {
+		t.Errorf("after pgdown: cursor %d, offset %d, want %d and %d", m.cursor, m.offset, 2*rows, rows+1)
+	}
+	m = press(m, "G")
+	if m.cursor != 199 || m.offset != 200-rows {
+		t.Errorf("after end: cursor %d, offset %d, want 199 and %d", m.cursor, m.offset, 200-rows)
+	}
+	m = press(m, "g")
+	if m.cursor != 0 || m.offset != 0 {
+		t.Errorf("after home: cursor %d, offset %d, want 0 and 0", m.cursor, m.offset)
+	}
+}
+
+func TestStickySelectionAtFixedHeight(t *testing.T) {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true})
+	m = update(m, manyProcesses(200))
+	m = update(m, tea.WindowSizeMsg{Width: 120, Height: 60})
+
+	m = press(m, "j", "j", "j")
+	if m.selectedPid != 4 {
+		t.Fatalf("selected pid %d, want 4", m.selectedPid)
+	}
+
+	// Pid 4 becomes the least busy process: the selection follows it to the last row.
+	procs := manyProcesses(200)
+	procs[3].CPUPercent = 0
+	m = update(m, procs)
+	if m.selectedPid != 4 || m.cursor != 199 || m.Processes[m.cursor].Pid != 4 {
+		t.Fatalf("selection moved to pid %d at row %d, want pid 4 at row 199", m.Processes[m.cursor].Pid, m.cursor)
+	}
+	view := m.View()
+	if got := strings.Count(view, "\n"); got != 60 {
+		t.Errorf("view has %d lines, want 60", got)
+	}
+	if !strings.Contains(view, selectedRowStyle.Render(processRow(m.opts.ProcessColumns, m.Processes[199]))) {
+		t.Error("the selected row should be scrolled into view")
+	}
+}
+
+func TestSummaryTallerThanTerminal(t *testing.T) {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true})
+	m = update(m, manyProcesses(200))
+	m = update(m, tea.WindowSizeMsg{Width: 120, Height: 20})
+
+	// The summary is cut so that the list keeps its minimum rows and the view still fits.
+	if rows := m.processListHeight(); rows != 5 {
+		t.Errorf("process list height = %d, want 5", rows)
+	}
+	if got := strings.Count(m.View(), "\n"); got != 20 {
+		t.Errorf("view has %d lines, want 20", got)
+	}
+}
+
+func TestSortKeys(t *testing.T) {
+	tests := []struct {
+		keys  []string
+		by    string
+		order int
+	}{
+		{[]string{"c"}, "cpu", 1}, // Already sorted by CPU, so the order flips
+		{[]string{"m"}, "mem", -1},
+		{[]string{"m", "m"}, "mem", 1},
+		{[]string{"p"}, "pid", 1},
+		{[]string{"p", "p"}, "pid", -1},
+		{[]string{"n"}, "name", 1},
+		{[]string{"n", "c"}, "cpu", -1},
+		{[]string{"r"}, "read", -1},
+		{[]string{"w", "w"}, "write", 1},
+		{[]string{"f"}, "fds", -1},
+	}
+	for _, tt := range tests {
+		m := press(newTestModel(nil), tt.keys...)
+		if m.sortBy != tt.by || m.sortOrder != tt.order {
+			t.Errorf("keys %v: sorted by %s/%d, want %s/%d", tt.keys, m.sortBy, m.sortOrder, tt.by, tt.order)
+		}
+	}
+
+	m := press(newTestModel(nil), "p", "p")
+	if got := m.Processes[0].Pid; got != 300 {
+		t.Errorf("descending pid order starts with %d, want 300", got)
+	}
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// signalPrompt is the state of the signal picker opened with 'x' or 'X'.
+type signalPrompt struct {
+	pids    []int32
+	target  string // Human readable description of pids
+	cursor  int    // Index into hundler.Signals; len(hundler.Signals) selects a custom number
+	editing bool   // True while a custom signal number is being typed
+	custom  string
+	confirm bool // True once a signal is chosen and awaits y/n
+	signal  syscall.Signal
+}
+
+// openSignalPrompt opens the signal picker for the selected process, or for every
+// process matching the filter when all is true.
+func (m *MainModel) openSignalPrompt(all bool) {
+	if all {
+		if m.filter.Empty() {
+			m.status = "Set a filter with '/' before signalling all matching processes"
+			return
+		}
+		if len(m.Matched) == 0 {
+			m.status = "No processes match the filter"
+			return
+		}
+		pids := make([]int32, len(m.Matched))
+		for i, p := range m.Matched {
+			pids[i] = p.Pid
+		}
+		m.prompt = &signalPrompt{pids: pids, target: fmt.Sprintf("%d processes matching %q", len(pids), m.filter.String())}
+		return
+	}
+	p, ok := m.selectedProcess()
+	if !ok {
+		return
+	}
+	m.prompt = &signalPrompt{pids: []int32{p.Pid}, target: fmt.Sprintf("pid %d (%s)", p.Pid, p.Name)}
+}
+
+// updateSignalPrompt handles key presses while the signal picker is open.
+func (m MainModel) updateSignalPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.prompt
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+	if key == "esc" {
+		m.prompt = nil
+		m.status = "Cancelled"
+		return m, nil
+	}
+
+	switch {
+	case p.confirm:
+		switch key {
+		case "y", "Y":
+			m.status = signalStatus(p.signal, hundler.SignalAll(m.signals, p.pids, p.signal))
+			m.prompt = nil
+		case "n", "N":
+			p.confirm = false
+		}
+	case p.editing:
+		switch {
+		case key == "enter":
+			n, err := strconv.Atoi(p.custom)
+			if err != nil || n <= 0 {
+				m.status = fmt.Sprintf("Invalid signal number %q", p.custom)
+				return m, nil
+			}
+			p.signal = syscall.Signal(n)
+			p.editing, p.confirm = false, true
+		case key == "backspace":
+			if len(p.custom) > 0 {
+				p.custom = p.custom[:len(p.custom)-1]
+			}
+		case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+			p.custom += key
+		}
+	default:
+		switch key {
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case "down", "j":
+			if p.cursor < len(hundler.Signals) {
+				p.cursor++
+			}
+		case "enter":
+			if p.cursor == len(hundler.Signals) {
+				p.editing = true
+				return m, nil
+			}
+			p.signal = hundler.Signals[p.cursor].Signal
+			p.confirm = true
+		}
+	}
+	return m, nil
+}
+
+// signalStatus summarises the outcome of sending a signal for the status bar.
+func signalStatus(sig syscall.Signal, results []hundler.SignalResult) string {
+	name := hundler.SignalName(sig)
+	var failed []hundler.SignalResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	switch {
+	case len(failed) == 0 && len(results) == 1:
+		return fmt.Sprintf("Sent %s to pid %d", name, results[0].Pid)
+	case len(failed) == 0:
+		return fmt.Sprintf("Sent %s to %d processes", name, len(results))
+	case len(results) == 1:
+		return fmt.Sprintf("%s to pid %d failed: %v", name, failed[0].Pid, failed[0].Err)
+	default:
+		return fmt.Sprintf("%s failed for %d of %d processes (pid %d: %v)", name, len(failed), len(results), failed[0].Pid, failed[0].Err)
+	}
+}
+
+// render renders the signal picker or its confirmation question.
+func (p *signalPrompt) render() string {
+	var b strings.Builder
+	if p.confirm {
+		fmt.Fprintf(&b, "Send %s to %s? (y/n)\n", hundler.SignalName(p.signal), p.target)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Send signal to %s (enter to choose, esc to cancel):\n", p.target)
+	for i, s := range hundler.Signals {
+		line := fmt.Sprintf("  %-8s (%d)", s.Name, int(s.Signal))
+		if i == p.cursor {
+			line = selectedRowStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	custom := "  custom number"
+	if p.editing {
+		custom = "  custom number: " + p.custom + "█"
+	}
+	if p.cursor == len(hundler.Signals) {
+		custom = selectedRowStyle.Render(custom)
+	}
+	b.WriteString(custom + "\n")
+	return b.String()
+}
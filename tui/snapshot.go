@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"fmt"
+)
+
+// RenderSnapshot renders a snapshot as plain text with the same panels as the
+// interactive view, followed by every process matching the filter when processes
+// are shown. It is used for batch output, where there is no terminal to draw on.
+func RenderSnapshot(s hundler.Snapshot, opts Options) string {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, opts)
+	m.LastUpdate = s.Time
+	m.CpuStat = s.Cpu
+	m.RamStat = s.Ram
+	m.Disks = s.Disks
+	m.DiskIO = s.DiskIO
+	m.NetStat = s.Net
+	m.Interfaces = s.Interfaces
+	m.LoadStat = s.Load
+	m.Pressure = s.Pressure
+	m.AllProcesses = s.Processes
+	m.applyFilter()
+
+	out := m.renderSummary()
+	if opts.ShowProcesses {
+		filter := ""
+		if !m.filter.Empty() {
+			filter = fmt.Sprintf("   Filter: %s", m.filter)
+		}
+		out += fmt.Sprintf("Processes: %d%s\n", len(m.Processes), filter)
+		out += processHeader(m.opts.ProcessColumns) + "\n"
+		for _, p := range m.Processes {
+			out += processRow(m.opts.ProcessColumns, p) + "\n"
+		}
+	}
+	return out
+}
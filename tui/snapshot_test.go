@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderSnapshot(t *testing.T) {
+	snap := hundler.Snapshot{
+		Time:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Cpu:       hundler.CpuStat{Percent: 12.5},
+		Load:      hundler.LoadStat{Load1: 4, Load5: 2, Load15: 1, Cores: 8},
+		Processes: treeFixture(),
+	}
+	out := RenderSnapshot(snap, Options{ShowProcesses: true, ProcessFilter: "node"})
+	for _, want := range []string{"Wed, 01 May 2024 12:00:00 UTC", " 12.50%", "(0.50 0.25 0.12 per core)", "Processes: 2   Filter: node", "PID"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	// Rows are sorted by CPU, busiest first, and never highlighted.
+	if strings.Index(out, "\n12 ") > strings.Index(out, "\n11 ") || strings.Contains(out, "\x1b[") {
+		t.Errorf("unexpected process rows:\n%s", out)
+	}
+
+	if out := RenderSnapshot(snap, Options{}); strings.Contains(out, "Processes:") {
+		t.Error("processes should only be listed when ShowProcesses is set")
+	}
+
+	// Without a core count the load isn't normalized at all rather than by a guess.
+	snap.Load.Cores = 0
+	if out := RenderSnapshot(snap, Options{}); !strings.Contains(out, "Load: 4.00 2.00 1.00   Tasks:") {
+		t.Errorf("unexpected load line without a core count:\n%s", out)
+	}
+}
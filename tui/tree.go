@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"fmt"
+	"sort"
+)
+
+// treeRow holds the tree layout of one visible row of the process table.
+type treeRow struct {
+	prefix      string              // Indentation glyphs drawn before the process label
+	descendants int                 // Number of processes below this one
+	collapsed   bool                // True if the subtree is folded into this row
+	total       hundler.ProcessStat // The process with its descendants' usage added, shown when collapsed
+}
+
+// buildProcessTree arranges procs by parent pid and returns the visible rows in display
+// order together with their layout. Processes whose parent is not in procs become roots.
+// Siblings are ordered by less, and the subtrees of pids in collapsed are hidden.
+func buildProcessTree(procs []hundler.ProcessStat, less func(a, b hundler.ProcessStat) bool, collapsed map[int32]bool) ([]hundler.ProcessStat, []treeRow) {
+	index := make(map[int32]int, len(procs))
+	for i, p := range procs {
+		index[p.Pid] = i
+	}
+	children := make(map[int32][]int)
+	var roots []int
+	for i, p := range procs {
+		if _, ok := index[p.PPid]; ok && p.PPid != p.Pid {
+			children[p.PPid] = append(children[p.PPid], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	byLess := func(idx []int) {
+		sort.SliceStable(idx, func(i, j int) bool { return less(procs[idx[i]], procs[idx[j]]) })
+	}
+	byLess(roots)
+	for _, idx := range children {
+		byLess(idx)
+	}
+
+	rows := make([]hundler.ProcessStat, 0, len(procs))
+	layout := make([]treeRow, 0, len(procs))
+	visited := make(map[int32]bool, len(procs))
+	// walk emits the subtree of procs[i] if visible and returns its aggregated usage.
+	var walk func(i int, prefix, indent string, visible bool) (hundler.ProcessStat, int)
+	walk = func(i int, prefix, indent string, visible bool) (hundler.ProcessStat, int) {
+		p := procs[i]
+		visited[p.Pid] = true
+		row := -1
+		if visible {
+			row = len(rows)
+			rows = append(rows, p)
+			layout = append(layout, treeRow{prefix: prefix, collapsed: collapsed[p.Pid]})
+		}
+		total, descendants := p, 0
+		kids := children[p.Pid]
+		for n, c := range kids {
+			if visited[procs[c].Pid] {
+				continue
+			}
+			branch, next := "├─ ", "│  "
+			if n == len(kids)-1 {
+				branch, next = "└─ ", "   "
+			}
+			sub, count := walk(c, indent+branch, indent+next, visible && !collapsed[p.Pid])
+			addUsage(&total, sub)
+			descendants += count + 1
+		}
+		if row >= 0 {
+			layout[row].descendants = descendants
+			layout[row].total = total
+		}
+		return total, descendants
+	}
+	for _, i := range roots {
+		walk(i, "", "", true)
+	}
+	// Parent loops have no root; show their members at the top level.
+	for i, p := range procs {
+		if !visited[p.Pid] {
+			walk(i, "", "", true)
+		}
+	}
+	return rows, layout
+}
+
+// addUsage adds the resource usage of p to total.
+func addUsage(total *hundler.ProcessStat, p hundler.ProcessStat) {
+	total.CPUPercent += p.CPUPercent
+	total.MemoryBytes += p.MemoryBytes
+	total.VMS += p.VMS
+	total.MemoryPercent += p.MemoryPercent
+	total.ReadBytesPerSec += p.ReadBytesPerSec
+	total.WriteBytesPerSec += p.WriteBytesPerSec
+	total.NumFDs += p.NumFDs
+	total.NumThreads += p.NumThreads
+}
+
+// treeLabelColumn returns the column that carries the tree glyphs: the name if it is
+// shown, otherwise the command line.
+func treeLabelColumn(names []string) string {
+	for _, want := range []string{"name", "cmdline"} {
+		for _, name := range names {
+			if name == want {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// decorate returns the process as shown in the tree: with its indentation glyphs and,
+// when collapsed, with the usage of its whole subtree.
+func (r treeRow) decorate(p hundler.ProcessStat, label string) hundler.ProcessStat {
+	suffix := ""
+	if r.collapsed && r.descendants > 0 {
+		p = r.total
+		suffix = fmt.Sprintf(" [+%d]", r.descendants)
+	}
+	switch label {
+	case "name":
+		p.Name = r.prefix + p.Name + suffix
+	case "cmdline":
+		p.Cmdline = r.prefix + p.Cmdline + suffix
+	}
+	return p
+}
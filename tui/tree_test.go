@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"basicsystemmonitor/hundler"
+	"testing"
+)
+
+func treeFixture() []hundler.ProcessStat {
+	return []hundler.ProcessStat{
+		{Pid: 1, PPid: 0, Name: "init", CPUPercent: 1, MemoryBytes: 10},
+		{Pid: 10, PPid: 1, Name: "npm", CPUPercent: 2, MemoryBytes: 100},
+		{Pid: 11, PPid: 10, Name: "node", CPUPercent: 30, MemoryBytes: 300},
+		{Pid: 12, PPid: 10, Name: "node", CPUPercent: 50, MemoryBytes: 500},
+		{Pid: 20, PPid: 1, Name: "sshd", CPUPercent: 5, MemoryBytes: 50},
+		{Pid: 30, PPid: 999, Name: "orphan", CPUPercent: 0, MemoryBytes: 1},
+	}
+}
+
+func byCPUDesc(a, b hundler.ProcessStat) bool { return a.CPUPercent > b.CPUPercent }
+
+func TestBuildProcessTree(t *testing.T) {
+	rows, layout := buildProcessTree(treeFixture(), byCPUDesc, map[int32]bool{})
+
+	want := []struct {
+		pid    int32
+		prefix string
+	}{
+		{1, ""},
+		{20, "├─ "},
+		{10, "└─ "},
+		{12, "   ├─ "},
+		{11, "   └─ "},
+		{30, ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		if rows[i].Pid != w.pid || layout[i].prefix != w.prefix {
+			t.Errorf("row %d = pid %d %q, want pid %d %q", i, rows[i].Pid, layout[i].prefix, w.pid, w.prefix)
+		}
+	}
+	if layout[0].descendants != 4 || layout[2].descendants != 2 || layout[3].descendants != 0 {
+		t.Errorf("descendants = %d, %d, %d, want 4, 2, 0", layout[0].descendants, layout[2].descendants, layout[3].descendants)
+	}
+}
+
+func TestBuildProcessTreeCollapsed(t *testing.T) {
+	rows, layout := buildProcessTree(treeFixture(), byCPUDesc, map[int32]bool{10: true})
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4 with npm's children hidden", len(rows))
+	}
+	npm := layout[2].decorate(rows[2], "name")
+	if npm.Name != "└─ npm [+2]" {
+		t.Errorf("name = %q, want %q", npm.Name, "└─ npm [+2]")
+	}
+	if npm.CPUPercent != 82 || npm.MemoryBytes != 900 {
+		t.Errorf("collapsed totals = %.0f%% %d, want 82%% 900", npm.CPUPercent, npm.MemoryBytes)
+	}
+	// Expanded rows keep their own usage.
+	if sshd := layout[1].decorate(rows[1], "name"); sshd.CPUPercent != 5 {
+		t.Errorf("sshd cpu = %.0f, want 5", sshd.CPUPercent)
+	}
+}
+
+func TestBuildProcessTreeParentLoop(t *testing.T) {
+	procs := []hundler.ProcessStat{
+		{Pid: 2, PPid: 3, Name: "a"},
+		{Pid: 3, PPid: 2, Name: "b"},
+	}
+	rows, _ := buildProcessTree(procs, byCPUDesc, map[int32]bool{})
+	if len(rows) != 2 {
+		t.Errorf("got %d rows, want both processes of the loop", len(rows))
+	}
+}
+
+func TestTreeModeCollapseSelected(t *testing.T) {
+	m := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, Options{ShowProcesses: true})
+	next, _ := m.Update(processMsg(treeFixture()))
+	m = next.(MainModel)
+
+	m = press(m, "t", "g", "down", "down", " ")
+	if m.Processes[m.cursor].Pid != 10 || len(m.Processes) != 4 {
+		t.Fatalf("cursor on pid %d with %d rows, want npm collapsed with 4 rows", m.Processes[m.cursor].Pid, len(m.Processes))
+	}
+	// A refresh keeps the subtree folded and the selection in place.
+	next, _ = m.Update(processMsg(treeFixture()))
+	m = next.(MainModel)
+	if len(m.Processes) != 4 || m.Processes[m.cursor].Pid != 10 {
+		t.Errorf("after refresh: %d rows, cursor on pid %d", len(m.Processes), m.Processes[m.cursor].Pid)
+	}
+	m = press(m, " ", "t")
+	if len(m.Processes) != 6 || m.tree != nil {
+		t.Errorf("flat list has %d rows, tree = %v", len(m.Processes), m.tree)
+	}
+}